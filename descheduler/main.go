@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/BU-KaveyZheng/CS476-Project/metrics"
+	"github.com/BU-KaveyZheng/CS476-Project/pkg/carbon"
+)
+
+const (
+	migratableLabel    = "carbon-aware"
+	migratableValue    = "migratable"
+	regionLabel        = "carbon-region" // Node label to map to Wattime region
+	defaultRegionLabel = "region"        // Fallback label
+
+	defaultInterval           = 5 * time.Minute
+	defaultDeltaThreshold     = 50.0 // g CO2/kWh
+	defaultNamespaceRateLimit = 1    // evictions per namespace per cycle
+	defaultCooldown           = 15 * time.Minute
+)
+
+var (
+	deschedulerInterval  = parseDurationOrDefault(os.Getenv("DESCHEDULE_INTERVAL"), defaultInterval)
+	carbonDeltaThreshold = parseFloatOrDefault(os.Getenv("CARBON_DELTA_THRESHOLD"), defaultDeltaThreshold)
+	namespaceRateLimit   = parseIntOrDefault(os.Getenv("NAMESPACE_RATE_LIMIT"), defaultNamespaceRateLimit)
+	cooldownPeriod       = parseDurationOrDefault(os.Getenv("COOLDOWN_PERIOD"), defaultCooldown)
+)
+
+func parseDurationOrDefault(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Printf("Warning: invalid duration %q, using default %s\n", value, fallback)
+		return fallback
+	}
+	return d
+}
+
+func parseFloatOrDefault(value string, fallback float64) float64 {
+	if value == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		fmt.Printf("Warning: invalid float %q, using default %.2f\n", value, fallback)
+		return fallback
+	}
+	return f
+}
+
+func parseIntOrDefault(value string, fallback int) int {
+	if value == "" {
+		return fallback
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		fmt.Printf("Warning: invalid int %q, using default %d\n", value, fallback)
+		return fallback
+	}
+	return i
+}
+
+func main() {
+	config, err := clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+	if err != nil {
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		panic(err.Error())
+	}
+	fmt.Println("Connected to Kubernetes API")
+	fmt.Printf("Carbon-aware descheduler: interval=%s threshold=%.1f gCO2/kWh cooldown=%s rate-limit=%d/namespace/cycle\n",
+		deschedulerInterval, carbonDeltaThreshold, cooldownPeriod, namespaceRateLimit)
+
+	if metricsAddr := os.Getenv("METRICS_ADDR"); metricsAddr != "" {
+		go func() {
+			fmt.Printf("Serving Prometheus metrics on %s/metrics\n", metricsAddr)
+			if err := metrics.Serve(metricsAddr); err != nil {
+				fmt.Printf("Metrics server stopped: %v\n", err)
+			}
+		}()
+	}
+
+	d := newDescheduler(clientset)
+	for {
+		d.runOnce()
+		time.Sleep(deschedulerInterval)
+	}
+}
+
+// descheduler periodically re-evaluates migratable pods against the carbon
+// cache and evicts those stuck in a region that's grown much worse than the
+// best one currently known, pacing evictions with a per-pod cooldown so
+// oscillating carbon intensities don't cause thrashing.
+type descheduler struct {
+	clientset *kubernetes.Clientset
+
+	mu        sync.Mutex
+	cooldowns map[types.UID]time.Time // pod UID -> last eviction time
+}
+
+func newDescheduler(clientset *kubernetes.Clientset) *descheduler {
+	return &descheduler{
+		clientset: clientset,
+		cooldowns: make(map[types.UID]time.Time),
+	}
+}
+
+// runOnce evaluates every carbon-aware=migratable pod once and evicts the
+// ones worth relocating, up to namespaceRateLimit evictions per namespace.
+func (d *descheduler) runOnce() {
+	ctx := context.Background()
+	intensities, err := fetchRegionIntensities(ctx)
+	if err != nil {
+		fmt.Printf("Descheduler: skipping cycle, carbon provider unavailable: %v\n", err)
+		return
+	}
+
+	bestRegion, bestIntensity, ok := bestKnownRegion(intensities)
+	if !ok {
+		fmt.Println("Descheduler: no region data from carbon provider, skipping cycle")
+		return
+	}
+
+	pods, err := d.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", migratableLabel, migratableValue),
+	})
+	if err != nil {
+		fmt.Printf("Descheduler: error listing migratable pods: %v\n", err)
+		return
+	}
+
+	evictedInNamespace := map[string]int{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if !hasEvictableOwner(pod) {
+			fmt.Printf("Descheduler: pod %s/%s has no ReplicaSet/Deployment/StatefulSet owner, skipping\n", pod.Namespace, pod.Name)
+			continue
+		}
+		if d.inCooldown(pod.UID) {
+			continue
+		}
+		if evictedInNamespace[pod.Namespace] >= namespaceRateLimit {
+			fmt.Printf("Descheduler: namespace %s at rate limit (%d/cycle), deferring pod %s\n", pod.Namespace, namespaceRateLimit, pod.Name)
+			continue
+		}
+
+		node, err := d.clientset.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+		if err != nil {
+			fmt.Printf("Descheduler: could not get node %s for pod %s/%s: %v\n", pod.Spec.NodeName, pod.Namespace, pod.Name, err)
+			continue
+		}
+
+		region := getNodeRegion(node)
+		if region == "" {
+			continue
+		}
+		intensity, ok := intensities[region]
+		if !ok {
+			continue
+		}
+
+		delta := intensity - bestIntensity
+		if delta <= carbonDeltaThreshold {
+			continue
+		}
+
+		fmt.Printf("Descheduler: pod %s/%s on node %s (region=%s, %.1f gCO2/kWh) is %.1f worse than best region %s (%.1f gCO2/kWh); evicting\n",
+			pod.Namespace, pod.Name, node.Name, region, intensity, delta, bestRegion, bestIntensity)
+
+		if d.evict(pod) {
+			evictedInNamespace[pod.Namespace]++
+			d.markCooldown(pod.UID)
+		}
+	}
+}
+
+// hasEvictableOwner reports whether pod is owned by a ReplicaSet, Deployment,
+// or StatefulSet, so evicting it lets that controller reschedule a
+// replacement (through the custom scheduler) rather than leaving the pod
+// permanently gone.
+func hasEvictableOwner(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		switch ref.Kind {
+		case "ReplicaSet", "Deployment", "StatefulSet":
+			return true
+		}
+	}
+	return false
+}
+
+// inCooldown reports whether pod's UID was evicted within cooldownPeriod.
+func (d *descheduler) inCooldown(uid types.UID) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	last, ok := d.cooldowns[uid]
+	if !ok {
+		return false
+	}
+	return time.Since(last) < cooldownPeriod
+}
+
+// markCooldown records uid as evicted at the current time.
+func (d *descheduler) markCooldown(uid types.UID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cooldowns[uid] = time.Now()
+}
+
+// evict evicts pod through the policy/v1 Eviction API, so any
+// PodDisruptionBudget protecting it is honored. Returns whether the eviction
+// succeeded.
+func (d *descheduler) evict(pod *corev1.Pod) bool {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+	if err := d.clientset.PolicyV1().Evictions(pod.Namespace).Evict(context.Background(), eviction); err != nil {
+		fmt.Printf("Descheduler: failed to evict pod %s/%s: %v\n", pod.Namespace, pod.Name, err)
+		metrics.DeschedulerEvictionsTotal.WithLabelValues(pod.Namespace, "error").Inc()
+		return false
+	}
+	fmt.Printf("Descheduler: evicted pod %s/%s\n", pod.Namespace, pod.Name)
+	metrics.DeschedulerEvictionsTotal.WithLabelValues(pod.Namespace, "evicted").Inc()
+	return true
+}
+
+// bestKnownRegion returns the region with the lowest carbon intensity among
+// intensities.
+func bestKnownRegion(intensities map[string]float64) (region string, intensity float64, ok bool) {
+	first := true
+	for name, v := range intensities {
+		if first || v < intensity {
+			intensity = v
+			region = name
+			first = false
+		}
+	}
+	return region, intensity, !first
+}
+
+// fetchRegionIntensities loads the CARBON_PROVIDER-selected provider (see
+// pkg/carbon) and resolves its current intensity for every region it knows
+// about, so the descheduler sees the same carbon data source - and the same
+// forecast-capable providers (WattTime, Electricity Maps, static YAML, chain
+// fallback) - as the scheduler, instead of reading the on-disk cache file
+// directly.
+func fetchRegionIntensities(ctx context.Context) (map[string]float64, error) {
+	provider, err := carbon.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	regions, err := provider.Regions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	intensities := make(map[string]float64, len(regions))
+	for _, region := range regions {
+		intensity, err := provider.CurrentIntensity(ctx, region)
+		if err != nil {
+			fmt.Printf("Descheduler: could not resolve intensity for region %s: %v\n", region, err)
+			continue
+		}
+		intensities[region] = intensity
+	}
+	return intensities, nil
+}
+
+// getNodeRegion resolves node's carbon region from its labels.
+func getNodeRegion(node *corev1.Node) string {
+	if region := node.Labels[regionLabel]; region != "" {
+		return region
+	}
+	if region := node.Labels[defaultRegionLabel]; region != "" {
+		return region
+	}
+	if zone := node.Labels["topology.kubernetes.io/zone"]; zone != "" {
+		return zone
+	}
+	return ""
+}
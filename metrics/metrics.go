@@ -0,0 +1,211 @@
+// Package metrics exposes Prometheus instrumentation for the carbon-aware
+// scheduler and simulator, following the pattern used by Azure's
+// kubernetes-carbon-intensity-exporter: per-zone gauges plus counters and
+// histograms for the components that produce scheduling decisions.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var (
+	// CarbonIntensity reports the last-known carbon intensity per zone.
+	CarbonIntensity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "carbon_intensity_gco2_per_kwh",
+		Help: "Latest carbon intensity in grams CO2 per kWh, per zone.",
+	}, []string{"zone"})
+
+	// CacheAgeSeconds reports how stale the on-disk carbon cache is.
+	CacheAgeSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "carbon_cache_seconds_since_update",
+		Help: "Seconds elapsed since the carbon intensity cache was last refreshed.",
+	})
+
+	// APICallsTotal counts CarbonClient requests by endpoint and outcome.
+	APICallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "carbon_client_api_calls_total",
+		Help: "Total CarbonClient API calls, by endpoint and result.",
+	}, []string{"endpoint", "result"})
+
+	// APICallDuration tracks CarbonClient request latency.
+	APICallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "carbon_client_api_call_duration_seconds",
+		Help:    "CarbonClient API call latency in seconds, by endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	// PodsScheduledTotal counts pods placed by the simulator/scheduler per mode and region.
+	PodsScheduledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "carbon_scheduler_pods_scheduled_total",
+		Help: "Total pods scheduled, by scheduling mode and region.",
+	}, []string{"mode", "region"})
+
+	// GramsSavedTotal accumulates estimated grams of CO2 saved by carbon-aware
+	// scheduling versus round-robin. See SchedulerCarbonGramsSavedTotal for the
+	// live scheduler's forecast-aware deferral savings specifically.
+	GramsSavedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "carbon_scheduler_grams_saved_total",
+		Help: "Cumulative grams of CO2 saved by carbon-aware scheduling versus round-robin.",
+	})
+
+	// JobsCompletedTotal counts jobs completed by the simulator, by scheduler and region.
+	JobsCompletedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "carbonsim_jobs_completed_total",
+		Help: "Total simulated jobs completed, by scheduler and region.",
+	}, []string{"scheduler", "region"})
+
+	// JobCarbonIntensity tracks the distribution of carbon intensity attributed
+	// to completed jobs, by scheduler and region.
+	JobCarbonIntensity = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "carbonsim_job_carbon_intensity",
+		Help:    "Carbon intensity (g CO2/kWh) attributed to completed simulated jobs, by scheduler and region.",
+		Buckets: prometheus.LinearBuckets(0, 50, 20),
+	}, []string{"scheduler", "region"})
+
+	// NodeCPUUtilization reports each node's mean CPU utilization over the run.
+	NodeCPUUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "carbonsim_node_cpu_utilization",
+		Help: "Mean CPU utilization percentage over the simulation run, by node.",
+	}, []string{"node"})
+
+	// LatencySeconds tracks job scheduling latency (queue + scheduling delay), by scheduler.
+	LatencySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "carbonsim_latency_seconds",
+		Help:    "Job latency (creation to start) in seconds, by scheduler.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+	}, []string{"scheduler"})
+
+	// TurnaroundSeconds tracks job turnaround time (creation to completion), by scheduler.
+	TurnaroundSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "carbonsim_turnaround_seconds",
+		Help:    "Job turnaround time (creation to completion) in seconds, by scheduler.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+	}, []string{"scheduler"})
+
+	// CarbonReductionRatio reports each scheduler's percentage carbon reduction
+	// versus the worst-case (Highest-Carbon) scheduler, written once at end-of-run.
+	CarbonReductionRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "carbonsim_carbon_reduction_ratio",
+		Help: "Percentage carbon reduction versus the worst-case scheduler, by scheduler.",
+	}, []string{"scheduler"})
+
+	// JobCarbonIntensityOverall mirrors JobCarbonIntensity without the region
+	// label, giving each scheduler a single histogram to derive an overall
+	// (not per-region) carbon intensity percentile from.
+	JobCarbonIntensityOverall = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "carbonsim_job_carbon_intensity_overall",
+		Help:    "Carbon intensity (g CO2/kWh) attributed to completed simulated jobs, by scheduler.",
+		Buckets: prometheus.LinearBuckets(0, 50, 20),
+	}, []string{"scheduler"})
+
+	// SubClusterLatencySeconds mirrors LatencySeconds but labeled by
+	// sub-cluster, so per-sub-cluster P95 latency can be derived for the
+	// "hotspots" report without retaining every job's raw latency.
+	SubClusterLatencySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "carbonsim_subcluster_latency_seconds",
+		Help:    "Job latency (creation to start) in seconds, by scheduler and sub-cluster.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+	}, []string{"scheduler", "subcluster"})
+
+	// DeschedulerEvictionsTotal counts the carbon-aware descheduler's eviction
+	// attempts, by namespace and outcome.
+	DeschedulerEvictionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "carbon_descheduler_evictions_total",
+		Help: "Total pod eviction attempts by the carbon-aware descheduler, by namespace and result.",
+	}, []string{"namespace", "result"})
+
+	// SchedulerCarbonDeferredPods counts pods the scheduler chose to leave
+	// unscheduled and requeue for a forecasted lower-carbon window, rather
+	// than scheduling immediately.
+	SchedulerCarbonDeferredPods = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "scheduler_carbon_deferred_pods",
+		Help: "Total pods deferred to a forecasted lower-carbon window instead of scheduled immediately.",
+	})
+
+	// SchedulerCarbonGramsSavedTotal accumulates the estimated grams of CO2
+	// saved by forecast-aware deferral versus scheduling immediately (see
+	// scheduler/forecast.go). Distinct from GramsSavedTotal, which tracks the
+	// simulator's carbon-aware-vs-round-robin comparison.
+	SchedulerCarbonGramsSavedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "scheduler_carbon_grams_saved_total",
+		Help: "Cumulative grams of CO2 estimated saved by forecast-aware deferred scheduling.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		CarbonIntensity,
+		CacheAgeSeconds,
+		APICallsTotal,
+		APICallDuration,
+		PodsScheduledTotal,
+		GramsSavedTotal,
+		JobsCompletedTotal,
+		JobCarbonIntensity,
+		NodeCPUUtilization,
+		LatencySeconds,
+		TurnaroundSeconds,
+		CarbonReductionRatio,
+		JobCarbonIntensityOverall,
+		SubClusterLatencySeconds,
+		DeschedulerEvictionsTotal,
+		SchedulerCarbonDeferredPods,
+		SchedulerCarbonGramsSavedTotal,
+	)
+}
+
+// HistogramPercentile estimates the p-th percentile (0-1) of a histogram's
+// observations from its cumulative bucket counts via linear interpolation
+// within the bucket that crosses the target rank. This lets callers derive
+// percentiles from Prometheus's HDR-style bounded buckets instead of
+// retaining every raw observation in memory.
+//
+// observer accepts the prometheus.Observer returned by a *Vec's
+// GetMetricWithLabelValues (the same method works for both Histogram and
+// Summary vecs), and is type-asserted to prometheus.Metric to reach Write.
+func HistogramPercentile(observer prometheus.Observer, p float64) float64 {
+	metric, ok := observer.(prometheus.Metric)
+	if !ok {
+		return 0
+	}
+	var m dto.Metric
+	if err := metric.Write(&m); err != nil {
+		return 0
+	}
+	h := m.GetHistogram()
+	buckets := h.GetBucket()
+	total := h.GetSampleCount()
+	if total == 0 {
+		return 0
+	}
+	target := p * float64(total)
+
+	var prevCount uint64
+	var prevBound float64
+	for _, b := range buckets {
+		count := b.GetCumulativeCount()
+		bound := b.GetUpperBound()
+		if float64(count) >= target {
+			if count == prevCount {
+				return bound
+			}
+			frac := (target - float64(prevCount)) / float64(count-prevCount)
+			return prevBound + frac*(bound-prevBound)
+		}
+		prevCount = count
+		prevBound = bound
+	}
+	return prevBound
+}
+
+// Serve starts a Prometheus /metrics HTTP endpoint on addr (e.g. ":9102") and
+// blocks until the server exits or an error occurs.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return http.ListenAndServe(addr, mux)
+}
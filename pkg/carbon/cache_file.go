@@ -0,0 +1,135 @@
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// cacheFileDocument mirrors the on-disk carbon_cache.json schema the
+// cluster's carbon-fetcher CronJob writes.
+type cacheFileDocument struct {
+	Timestamp  string                     `json:"timestamp"`
+	TTLMinutes int                        `json:"ttl_minutes"`
+	Regions    map[string]cacheFileRegion `json:"regions"`
+}
+
+type cacheFileRegion struct {
+	CarbonIntensity float64                  `json:"carbonIntensity"`
+	MOER            float64                  `json:"moer"`
+	Forecast        []cacheFileForecastPoint `json:"forecast"`
+}
+
+type cacheFileForecastPoint struct {
+	Timestamp       string  `json:"timestamp"`
+	CarbonIntensity float64 `json:"carbonIntensity"`
+}
+
+// CacheFileProvider reads carbon intensity from the on-disk JSON cache
+// populated by the cluster's carbon-fetcher CronJob - the default provider,
+// preserving today's behavior when CARBON_PROVIDER is unset.
+type CacheFileProvider struct {
+	Path string
+}
+
+// NewCacheFileProvider reads the carbon cache from path.
+func NewCacheFileProvider(path string) *CacheFileProvider {
+	return &CacheFileProvider{Path: path}
+}
+
+func (p *CacheFileProvider) load() (*cacheFileDocument, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	// First unmarshal into a generic map to handle the nested regions.regions
+	// shape some older fetcher script versions wrote.
+	var rawData map[string]interface{}
+	if err := json.Unmarshal(data, &rawData); err != nil {
+		return nil, fmt.Errorf("failed to parse cache: %w", err)
+	}
+	if regionsRaw, ok := rawData["regions"].(map[string]interface{}); ok {
+		if nestedRegions, ok := regionsRaw["regions"].(map[string]interface{}); ok {
+			rawData["regions"] = nestedRegions
+		}
+	}
+
+	var doc cacheFileDocument
+	rawBytes, _ := json.Marshal(rawData)
+	if err := json.Unmarshal(rawBytes, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cache: %w", err)
+	}
+
+	if doc.Timestamp != "" {
+		ts, err := time.Parse(time.RFC3339, doc.Timestamp)
+		if err == nil {
+			if age := time.Since(ts); age > time.Duration(doc.TTLMinutes)*time.Minute {
+				return nil, fmt.Errorf("cache expired (age: %v)", age)
+			}
+		}
+	}
+	return &doc, nil
+}
+
+func (p *CacheFileProvider) CurrentIntensity(ctx context.Context, region string) (float64, error) {
+	doc, err := p.load()
+	if err != nil {
+		return 0, err
+	}
+	r, ok := doc.Regions[region]
+	if !ok {
+		return 0, fmt.Errorf("region %q not in cache", region)
+	}
+	if r.CarbonIntensity > 0 {
+		return r.CarbonIntensity, nil
+	}
+	return r.MOER, nil
+}
+
+func (p *CacheFileProvider) Forecast(ctx context.Context, region string, horizon time.Duration) ([]ForecastPoint, error) {
+	doc, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	r, ok := doc.Regions[region]
+	if !ok {
+		return nil, fmt.Errorf("region %q not in cache", region)
+	}
+
+	now := time.Now()
+	deadline := now.Add(horizon)
+	points := make([]ForecastPoint, 0, len(r.Forecast))
+	for _, fp := range r.Forecast {
+		ts, err := time.Parse(time.RFC3339, fp.Timestamp)
+		if err != nil || ts.Before(now) || ts.After(deadline) {
+			continue
+		}
+		points = append(points, ForecastPoint{Timestamp: ts, CarbonIntensity: fp.CarbonIntensity})
+	}
+	return points, nil
+}
+
+func (p *CacheFileProvider) Regions(ctx context.Context) ([]string, error) {
+	doc, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	regions := make([]string, 0, len(doc.Regions))
+	for name := range doc.Regions {
+		regions = append(regions, name)
+	}
+	return regions, nil
+}
+
+func init() {
+	Register("cache-file", func() (CarbonProvider, error) {
+		path := os.Getenv("CACHE_FILE")
+		if path == "" {
+			path = "/cache/carbon_cache.json"
+		}
+		return NewCacheFileProvider(path), nil
+	})
+}
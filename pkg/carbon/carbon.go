@@ -0,0 +1,144 @@
+// Package carbon abstracts over carbon-intensity data sources (an on-disk
+// JSON cache, Electricity Maps, WattTime, or a static YAML fallback for
+// air-gapped clusters) behind a single CarbonProvider interface, so callers
+// like the scheduler and its cache-reader CLI don't need to know which
+// backend is actually configured. Mirrors the provider-chain approach
+// scheduler/carbon_provider.go already uses for the Electricity Maps/WattTime
+// clients, but as an importable package so it isn't tied to package main.
+package carbon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ForecastPoint is one forecasted carbon intensity sample for a region.
+type ForecastPoint struct {
+	Timestamp       time.Time
+	CarbonIntensity float64 // g CO2/kWh
+}
+
+// CarbonProvider is a source of carbon intensity data for one or more grid
+// regions/zones.
+type CarbonProvider interface {
+	// CurrentIntensity returns region's latest known carbon intensity in g CO2/kWh.
+	CurrentIntensity(ctx context.Context, region string) (float64, error)
+	// Forecast returns region's forecasted carbon intensity for the next horizon.
+	Forecast(ctx context.Context, region string, horizon time.Duration) ([]ForecastPoint, error)
+	// Regions lists every region/zone this provider has data for.
+	Regions(ctx context.Context) ([]string, error)
+}
+
+// Factory builds a CarbonProvider from its own env/file configuration, so
+// Register can defer construction (and any required credentials) until the
+// provider is actually selected.
+type Factory func() (CarbonProvider, error)
+
+var registry = map[string]Factory{}
+
+// Register adds or overrides a named provider factory. Concrete providers
+// register themselves from an init(), so New/Load can resolve a provider by
+// name without every caller importing each implementation explicitly.
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// New builds the named provider directly, bypassing CARBON_PROVIDER.
+func New(name string) (CarbonProvider, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown carbon provider %q", name)
+	}
+	return factory()
+}
+
+// Load resolves the CARBON_PROVIDER env var (default "cache-file", preserving
+// today's on-disk-cache behavior) against the registry and builds that provider.
+func Load() (CarbonProvider, error) {
+	name := os.Getenv("CARBON_PROVIDER")
+	if name == "" {
+		name = "cache-file"
+	}
+	return New(name)
+}
+
+// ChainProvider tries each provider in order, returning the first successful
+// result and falling through to the next on error - e.g. prefer a live HTTP
+// API but fall back to the on-disk cache or static YAML if it's unreachable.
+type ChainProvider struct {
+	providers []CarbonProvider
+}
+
+// NewChainProvider combines providers, consulted in order.
+func NewChainProvider(providers ...CarbonProvider) *ChainProvider {
+	return &ChainProvider{providers: providers}
+}
+
+func (c *ChainProvider) CurrentIntensity(ctx context.Context, region string) (float64, error) {
+	var lastErr error
+	for _, p := range c.providers {
+		intensity, err := p.CurrentIntensity(ctx, region)
+		if err == nil {
+			return intensity, nil
+		}
+		lastErr = err
+	}
+	return 0, fmt.Errorf("no provider could serve region %s: %w", region, lastErr)
+}
+
+func (c *ChainProvider) Forecast(ctx context.Context, region string, horizon time.Duration) ([]ForecastPoint, error) {
+	var lastErr error
+	for _, p := range c.providers {
+		points, err := p.Forecast(ctx, region, horizon)
+		if err == nil {
+			return points, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no provider could serve region %s: %w", region, lastErr)
+}
+
+func (c *ChainProvider) Regions(ctx context.Context) ([]string, error) {
+	var lastErr error
+	for _, p := range c.providers {
+		regions, err := p.Regions(ctx)
+		if err == nil {
+			return regions, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no provider could list regions: %w", lastErr)
+}
+
+func init() {
+	Register("chain", func() (CarbonProvider, error) {
+		cacheFileProvider, err := New("cache-file")
+		if err != nil {
+			return nil, err
+		}
+		providers := []CarbonProvider{cacheFileProvider}
+
+		if os.Getenv("ELECTRICITY_MAPS_API_KEY") != "" {
+			emProvider, err := New("electricity-maps")
+			if err == nil {
+				providers = append([]CarbonProvider{emProvider}, providers...)
+			}
+		}
+		if os.Getenv("WATTTIME_API_KEY") != "" {
+			wtProvider, err := New("watttime")
+			if err == nil {
+				providers = append([]CarbonProvider{wtProvider}, providers...)
+			}
+		}
+		if staticFile := os.Getenv("CARBON_STATIC_FILE"); staticFile != "" {
+			staticProvider, err := New("static-yaml")
+			if err == nil {
+				providers = append(providers, staticProvider)
+			}
+		}
+
+		return NewChainProvider(providers...), nil
+	})
+}
@@ -0,0 +1,112 @@
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ElectricityMapsProvider implements CarbonProvider against the Electricity
+// Maps v3 API directly, for clusters that want live data without the
+// separate fetcher script populating the on-disk cache.
+type ElectricityMapsProvider struct {
+	apiKey  string
+	regions []string
+	client  *http.Client
+}
+
+// NewElectricityMapsProvider creates an Electricity-Maps-backed provider.
+// regions is the fixed set of zones Regions() reports, since Electricity
+// Maps has no "list all zones we're entitled to" endpoint.
+func NewElectricityMapsProvider(apiKey string, regions []string) *ElectricityMapsProvider {
+	return &ElectricityMapsProvider{
+		apiKey:  apiKey,
+		regions: regions,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type electricityMapsDataPoint struct {
+	Zone            string    `json:"zone"`
+	CarbonIntensity float64   `json:"carbonIntensity"`
+	Datetime        time.Time `json:"datetime"`
+}
+
+type electricityMapsForecastResponse struct {
+	Zone string                     `json:"zone"`
+	Data []electricityMapsDataPoint `json:"forecast"`
+}
+
+func (p *ElectricityMapsProvider) doRequest(path, region string, params string) (*http.Response, error) {
+	url := fmt.Sprintf("https://api.electricitymaps.com/v3/%s?zone=%s%s", path, region, params)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("auth-token", p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Electricity Maps API request failed with status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (p *ElectricityMapsProvider) CurrentIntensity(ctx context.Context, region string) (float64, error) {
+	resp, err := p.doRequest("carbon-intensity/latest", region, "")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var point electricityMapsDataPoint
+	if err := json.NewDecoder(resp.Body).Decode(&point); err != nil {
+		return 0, fmt.Errorf("failed to decode Electricity Maps response: %w", err)
+	}
+	return point.CarbonIntensity, nil
+}
+
+func (p *ElectricityMapsProvider) Forecast(ctx context.Context, region string, horizon time.Duration) ([]ForecastPoint, error) {
+	hours := int(horizon.Hours())
+	if hours <= 0 {
+		hours = 1
+	}
+	resp, err := p.doRequest("carbon-intensity/forecast", region, fmt.Sprintf("&horizon=%d", hours))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var decoded electricityMapsForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode Electricity Maps forecast: %w", err)
+	}
+
+	points := make([]ForecastPoint, 0, len(decoded.Data))
+	for _, d := range decoded.Data {
+		points = append(points, ForecastPoint{Timestamp: d.Datetime, CarbonIntensity: d.CarbonIntensity})
+	}
+	return points, nil
+}
+
+func (p *ElectricityMapsProvider) Regions(ctx context.Context) ([]string, error) {
+	return p.regions, nil
+}
+
+func init() {
+	Register("electricity-maps", func() (CarbonProvider, error) {
+		apiKey := os.Getenv("ELECTRICITY_MAPS_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("ELECTRICITY_MAPS_API_KEY environment variable is required")
+		}
+		regions := splitRegionsEnv(os.Getenv("CARBON_PROVIDER_REGIONS"))
+		return NewElectricityMapsProvider(apiKey, regions), nil
+	})
+}
@@ -0,0 +1,104 @@
+package carbon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// staticYAMLDocument is the air-gapped fallback schema: a fixed
+// region -> carbon intensity mapping with no live or forecast signal,
+// e.g.:
+//
+//	regions:
+//	  us-east: 420
+//	  us-west: 180
+type staticYAMLDocument struct {
+	Regions map[string]float64 `yaml:"regions"`
+}
+
+// StaticYAMLProvider loads a fixed region -> carbon intensity mapping from a
+// YAML file for air-gapped clusters with no route to a live carbon API. It
+// has no concept of recency or forecasting, so Forecast repeats the static
+// value for each hour of the requested horizon.
+type StaticYAMLProvider struct {
+	intensities map[string]float64
+}
+
+// NewStaticYAMLProvider loads the region->intensity mapping from path.
+func NewStaticYAMLProvider(path string) (*StaticYAMLProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static YAML file: %w", err)
+	}
+
+	var doc staticYAMLDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse static YAML file: %w", err)
+	}
+
+	return &StaticYAMLProvider{intensities: doc.Regions}, nil
+}
+
+func (p *StaticYAMLProvider) CurrentIntensity(ctx context.Context, region string) (float64, error) {
+	intensity, ok := p.intensities[region]
+	if !ok {
+		return 0, fmt.Errorf("region %s not present in static YAML data", region)
+	}
+	return intensity, nil
+}
+
+func (p *StaticYAMLProvider) Forecast(ctx context.Context, region string, horizon time.Duration) ([]ForecastPoint, error) {
+	intensity, err := p.CurrentIntensity(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := int(horizon.Hours())
+	if hours <= 0 {
+		hours = 1
+	}
+	now := time.Now()
+	points := make([]ForecastPoint, hours)
+	for i := range points {
+		points[i] = ForecastPoint{Timestamp: now.Add(time.Duration(i) * time.Hour), CarbonIntensity: intensity}
+	}
+	return points, nil
+}
+
+func (p *StaticYAMLProvider) Regions(ctx context.Context) ([]string, error) {
+	regions := make([]string, 0, len(p.intensities))
+	for region := range p.intensities {
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+// splitRegionsEnv parses a comma-separated CARBON_PROVIDER_REGIONS value into
+// a region slice, trimming whitespace and dropping empty entries.
+func splitRegionsEnv(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var regions []string
+	for _, r := range strings.Split(value, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
+func init() {
+	Register("static-yaml", func() (CarbonProvider, error) {
+		path := os.Getenv("CARBON_STATIC_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("CARBON_STATIC_FILE environment variable is required")
+		}
+		return NewStaticYAMLProvider(path)
+	})
+}
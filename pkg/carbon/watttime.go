@@ -0,0 +1,123 @@
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// wattTimeMOERToGramsPerKWh converts WattTime's MOER, reported in lbs CO2/MWh,
+// to grams CO2/kWh so it can be compared directly against Electricity Maps data.
+func wattTimeMOERToGramsPerKWh(moerLbsPerMWh float64) float64 {
+	const gramsPerLb = 453.592
+	const kWhPerMWh = 1000.0
+	return moerLbsPerMWh * gramsPerLb / kWhPerMWh
+}
+
+// WattTimeProvider implements CarbonProvider against WattTime's v3 API,
+// converting its MOER (marginal operating emissions rate) to gCO2/kWh.
+type WattTimeProvider struct {
+	apiKey  string
+	regions []string
+	client  *http.Client
+}
+
+// NewWattTimeProvider creates a WattTime-backed provider. apiKey is the
+// bearer token obtained from WattTime's login endpoint. regions is the fixed
+// set of zones Regions() reports, since WattTime has no "list all regions
+// we're entitled to" endpoint.
+func NewWattTimeProvider(apiKey string, regions []string) *WattTimeProvider {
+	return &WattTimeProvider{
+		apiKey:  apiKey,
+		regions: regions,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type wattTimeIndexPoint struct {
+	PointTime time.Time `json:"point_time"`
+	Value     float64   `json:"value"`
+}
+
+type wattTimeIndexResponse struct {
+	Data []wattTimeIndexPoint `json:"data"`
+}
+
+func (p *WattTimeProvider) doRequest(path, region string, params map[string]string) (*wattTimeIndexResponse, error) {
+	url := fmt.Sprintf("https://api.watttime.org/v3/%s?region=%s", path, region)
+	for k, v := range params {
+		url += fmt.Sprintf("&%s=%s", k, v)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("WattTime API request failed with status %d", resp.StatusCode)
+	}
+
+	var decoded wattTimeIndexResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode WattTime response: %w", err)
+	}
+	return &decoded, nil
+}
+
+func (p *WattTimeProvider) CurrentIntensity(ctx context.Context, region string) (float64, error) {
+	resp, err := p.doRequest("index", region, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Data) == 0 {
+		return 0, fmt.Errorf("no index data returned for region %s", region)
+	}
+	return wattTimeMOERToGramsPerKWh(resp.Data[0].Value), nil
+}
+
+func (p *WattTimeProvider) Forecast(ctx context.Context, region string, horizon time.Duration) ([]ForecastPoint, error) {
+	hours := int(horizon.Hours())
+	if hours <= 0 {
+		hours = 1
+	}
+	resp, err := p.doRequest("forecast", region, map[string]string{"horizon_hours": strconv.Itoa(hours)})
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]ForecastPoint, 0, len(resp.Data))
+	for _, point := range resp.Data {
+		points = append(points, ForecastPoint{
+			Timestamp:       point.PointTime,
+			CarbonIntensity: wattTimeMOERToGramsPerKWh(point.Value),
+		})
+	}
+	return points, nil
+}
+
+func (p *WattTimeProvider) Regions(ctx context.Context) ([]string, error) {
+	return p.regions, nil
+}
+
+func init() {
+	Register("watttime", func() (CarbonProvider, error) {
+		apiKey := os.Getenv("WATTTIME_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("WATTTIME_API_KEY environment variable is required")
+		}
+		regions := splitRegionsEnv(os.Getenv("CARBON_PROVIDER_REGIONS"))
+		return NewWattTimeProvider(apiKey, regions), nil
+	})
+}
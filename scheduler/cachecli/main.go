@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/BU-KaveyZheng/CS476-Project/pkg/carbon"
+)
+
+// This is a thin CLI wrapper around pkg/carbon, printing whatever the
+// CARBON_PROVIDER-selected provider reports for each of its known regions -
+// useful for sanity-checking a provider's configuration (cache file path, API
+// key, static YAML file, ...) without standing up the scheduler itself.
+func main() {
+	provider, err := carbon.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading carbon provider: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	regions, err := provider.Regions(ctx)
+	if err != nil {
+		fmt.Printf("❌ Error listing regions: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Carbon provider ready (%d region(s))\n", len(regions))
+	fmt.Println("\n  Zone carbon intensities:")
+	for _, region := range regions {
+		intensity, err := provider.CurrentIntensity(ctx, region)
+		if err != nil {
+			fmt.Printf("    %s: error: %v\n", region, err)
+			continue
+		}
+		fmt.Printf("    %s: %.2f g CO2/kWh\n", region, intensity)
+	}
+}
@@ -7,14 +7,17 @@ import (
 	"net/http"
 	"os"
 	"time"
+
+	"github.com/BU-KaveyZheng/CS476-Project/metrics"
 )
 
 // CarbonIntensityData represents the structure of carbon intensity data from Electricity Maps API
 type CarbonIntensityData struct {
-	Zone            string    `json:"zone"`
-	CarbonIntensity int       `json:"carbonIntensity"`
-	Datetime        time.Time `json:"datetime"`
-	UpdatedAt       time.Time `json:"updatedAt"`
+	Zone            string         `json:"zone"`
+	CarbonIntensity int            `json:"carbonIntensity"`
+	Datetime        time.Time      `json:"datetime"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	EmissionFactor  EmissionFactor `json:"emissionFactor,omitempty"`
 }
 
 // CarbonIntensityResponse represents the API response structure
@@ -24,25 +27,125 @@ type CarbonIntensityResponse struct {
 
 // CarbonClient handles communication with the Electricity Maps API
 type CarbonClient struct {
-	apiKey string
-	client *http.Client
+	apiKey        string
+	client        *http.Client
+	maxWorkers    int
+	maxRetries    int
+	retryBaseWait time.Duration
+}
+
+// CarbonClientOptions configures batching and retry behavior for CarbonClient.
+// Zero values fall back to the same defaults NewCarbonClient uses.
+type CarbonClientOptions struct {
+	Timeout       time.Duration // HTTP client timeout, default 10s
+	MaxWorkers    int           // Concurrent workers for batch fetches, default 8
+	MaxRetries    int           // Retry attempts for 429/503 responses, default 3
+	RetryBaseWait time.Duration // Base backoff wait, doubled per retry, default 500ms
 }
 
-// NewCarbonClient creates a new carbon intensity client
+// NewCarbonClient creates a new carbon intensity client with default options.
 func NewCarbonClient() (*CarbonClient, error) {
+	return NewCarbonClientWithOptions(CarbonClientOptions{})
+}
+
+// NewCarbonClientWithOptions creates a carbon intensity client with a shared,
+// keep-alive http.Client and configurable batch worker pool / retry behavior.
+func NewCarbonClientWithOptions(opts CarbonClientOptions) (*CarbonClient, error) {
 	apiKey := os.Getenv("ELECTRICITY_MAPS_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("ELECTRICITY_MAPS_API_KEY environment variable is required")
 	}
 
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	maxWorkers := opts.MaxWorkers
+	if maxWorkers == 0 {
+		maxWorkers = 8
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 3
+	}
+	retryBaseWait := opts.RetryBaseWait
+	if retryBaseWait == 0 {
+		retryBaseWait = 500 * time.Millisecond
+	}
+
 	return &CarbonClient{
 		apiKey: apiKey,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: timeout,
+			Transport: &http.Transport{
+				MaxIdleConns:        maxWorkers * 2,
+				MaxIdleConnsPerHost: maxWorkers * 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
 		},
+		maxWorkers:    maxWorkers,
+		maxRetries:    maxRetries,
+		retryBaseWait: retryBaseWait,
 	}, nil
 }
 
+// doWithRetry issues req under the given metrics endpoint label, retrying on
+// HTTP 429/503 with exponential backoff and honoring the Retry-After header
+// (seconds or HTTP-date) when present.
+func (c *CarbonClient) doWithRetry(endpoint string, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	defer func() {
+		metrics.APICallDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	}()
+
+	wait := c.retryBaseWait
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		resp, err := c.client.Do(req)
+		if err != nil {
+			lastErr = err
+		} else if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), wait)
+			resp.Body.Close()
+			lastErr = fmt.Errorf("API request throttled with status %d", resp.StatusCode)
+			if attempt == c.maxRetries {
+				break
+			}
+			time.Sleep(retryAfter)
+			wait *= 2
+			continue
+		} else {
+			metrics.APICallsTotal.WithLabelValues(endpoint, "success").Inc()
+			return resp, nil
+		}
+
+		if attempt == c.maxRetries {
+			break
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+	metrics.APICallsTotal.WithLabelValues(endpoint, "error").Inc()
+	return nil, fmt.Errorf("request failed after %d attempts: %w", c.maxRetries+1, lastErr)
+}
+
+// parseRetryAfter parses a Retry-After header value (seconds or HTTP-date),
+// falling back to defaultWait if the header is absent or unparseable.
+func parseRetryAfter(header string, defaultWait time.Duration) time.Duration {
+	if header == "" {
+		return defaultWait
+	}
+	if seconds, err := time.ParseDuration(header + "s"); err == nil {
+		return seconds
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return defaultWait
+}
+
 // GetLatestCarbonIntensity retrieves the latest carbon intensity for a given zone
 func (c *CarbonClient) GetLatestCarbonIntensity(zone string) (*CarbonIntensityData, error) {
 	url := fmt.Sprintf("https://api.electricitymaps.com/v3/carbon-intensity/latest?zone=%s", zone)
@@ -55,7 +158,7 @@ func (c *CarbonClient) GetLatestCarbonIntensity(zone string) (*CarbonIntensityDa
 	req.Header.Set("auth-token", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.doWithRetry("latest", req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -91,7 +194,44 @@ func (c *CarbonClient) GetRecentCarbonIntensity(zone string) ([]CarbonIntensityD
 	req.Header.Set("auth-token", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.doWithRetry("recent", req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var response CarbonIntensityResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return response.Data, nil
+}
+
+// GetCarbonIntensityForecast retrieves hourly forecasted carbon intensity for a
+// given zone over the next `hours` hours, using Electricity Maps' forecast endpoint.
+func (c *CarbonClient) GetCarbonIntensityForecast(zone string, hours int) ([]CarbonIntensityData, error) {
+	url := fmt.Sprintf("https://api.electricitymaps.com/v3/carbon-intensity/forecast?zone=%s&horizon=%d", zone, hours)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("auth-token", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doWithRetry("forecast", req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -115,9 +255,41 @@ func (c *CarbonClient) GetRecentCarbonIntensity(zone string) ([]CarbonIntensityD
 	return response.Data, nil
 }
 
-// GetAverageCarbonIntensity calculates the average carbon intensity from recent data
-// Falls back to latest data if recent data is not available
-func (c *CarbonClient) GetAverageCarbonIntensity(zone string, hours int) (float64, error) {
+// EmissionFactor selects which operating-emissions convention to score carbon
+// intensity against. Average and marginal factors can rank regions differently:
+// average reflects the generation mix's typical emissions (Electricity Maps'
+// default), while marginal reflects the emissions of the next generator that
+// would respond to a change in load (what WattTime's MOER measures).
+type EmissionFactor int
+
+const (
+	Average EmissionFactor = iota
+	MarginalOperating
+	LifecycleAverage
+)
+
+func (f EmissionFactor) String() string {
+	switch f {
+	case MarginalOperating:
+		return "marginal"
+	case LifecycleAverage:
+		return "lifecycle-average"
+	default:
+		return "average"
+	}
+}
+
+// GetAverageCarbonIntensity calculates the average carbon intensity from recent
+// data for the given emission factor convention. Electricity Maps only serves
+// average (and lifecycle-average, which it reports under the same field) data;
+// callers that need marginal operating emissions should go through a
+// CarbonDataProvider backed by WattTimeProvider instead.
+// Falls back to latest data if recent data is not available.
+func (c *CarbonClient) GetAverageCarbonIntensity(zone string, hours int, factor EmissionFactor) (float64, error) {
+	if factor == MarginalOperating {
+		return 0, fmt.Errorf("electricity maps does not serve marginal operating emissions; use a WattTimeProvider")
+	}
+
 	// Try to get recent data first
 	recentData, err := c.GetRecentCarbonIntensity(zone)
 	if err == nil && len(recentData) > 0 {
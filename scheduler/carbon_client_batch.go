@@ -0,0 +1,84 @@
+package main
+
+import (
+	"sync"
+)
+
+// BatchError reports a per-zone failure from a batch fetch without aborting the
+// rest of the batch.
+type BatchError struct {
+	Zone string
+	Err  error
+}
+
+func (e *BatchError) Error() string {
+	return e.Zone + ": " + e.Err.Error()
+}
+
+// GetLatestCarbonIntensityBatch fetches the latest carbon intensity for each zone
+// concurrently, bounded by the client's worker pool. Zones that fail are omitted
+// from the result map and reported as BatchErrors rather than aborting the batch.
+func (c *CarbonClient) GetLatestCarbonIntensityBatch(zones []string) (map[string]*CarbonIntensityData, []BatchError) {
+	results := make(map[string]*CarbonIntensityData, len(zones))
+	var errs []BatchError
+	var mu sync.Mutex
+
+	sem := make(chan struct{}, c.maxWorkers)
+	var wg sync.WaitGroup
+
+	for _, zone := range zones {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(zone string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			data, err := c.GetLatestCarbonIntensity(zone)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, BatchError{Zone: zone, Err: err})
+				return
+			}
+			results[zone] = data
+		}(zone)
+	}
+
+	wg.Wait()
+	return results, errs
+}
+
+// GetAverageCarbonIntensityBatch computes the average carbon intensity over the
+// last `hours` hours for each zone concurrently, bounded by the client's worker
+// pool, resolved against factor the same way the single-zone
+// GetAverageCarbonIntensity is. Zones that fail are omitted from the result map
+// and reported as BatchErrors.
+func (c *CarbonClient) GetAverageCarbonIntensityBatch(zones []string, hours int, factor EmissionFactor) (map[string]float64, []BatchError) {
+	results := make(map[string]float64, len(zones))
+	var errs []BatchError
+	var mu sync.Mutex
+
+	sem := make(chan struct{}, c.maxWorkers)
+	var wg sync.WaitGroup
+
+	for _, zone := range zones {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(zone string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			avg, err := c.GetAverageCarbonIntensity(zone, hours, factor)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, BatchError{Zone: zone, Err: err})
+				return
+			}
+			results[zone] = avg
+		}(zone)
+	}
+
+	wg.Wait()
+	return results, errs
+}
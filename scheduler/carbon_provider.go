@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CarbonDataProvider abstracts over carbon intensity data sources so the
+// scheduler isn't hardwired to a single API (Electricity Maps, WattTime, or a
+// static offline dataset).
+type CarbonDataProvider interface {
+	GetLatest(zone string) (*CarbonIntensityData, error)
+	GetRecent(zone string) ([]CarbonIntensityData, error)
+	GetForecast(zone string, hours int) ([]CarbonIntensityData, error)
+	Name() string
+}
+
+// ElectricityMapsProvider adapts the existing CarbonClient to CarbonDataProvider.
+type ElectricityMapsProvider struct {
+	client *CarbonClient
+}
+
+// NewElectricityMapsProvider wraps an existing CarbonClient as a CarbonDataProvider.
+func NewElectricityMapsProvider(client *CarbonClient) *ElectricityMapsProvider {
+	return &ElectricityMapsProvider{client: client}
+}
+
+func (p *ElectricityMapsProvider) GetLatest(zone string) (*CarbonIntensityData, error) {
+	return p.client.GetLatestCarbonIntensity(zone)
+}
+
+func (p *ElectricityMapsProvider) GetRecent(zone string) ([]CarbonIntensityData, error) {
+	return p.client.GetRecentCarbonIntensity(zone)
+}
+
+func (p *ElectricityMapsProvider) GetForecast(zone string, hours int) ([]CarbonIntensityData, error) {
+	return p.client.GetCarbonIntensityForecast(zone, hours)
+}
+
+func (p *ElectricityMapsProvider) Name() string {
+	return "electricity-maps"
+}
+
+// wattTimeMOERToGramsPerKWh converts WattTime's MOER, reported in lbs CO2/MWh,
+// to grams CO2/kWh so it can be compared directly against Electricity Maps data.
+func wattTimeMOERToGramsPerKWh(moerLbsPerMWh float64) float64 {
+	const gramsPerLb = 453.592
+	const kWhPerMWh = 1000.0
+	return moerLbsPerMWh * gramsPerLb / kWhPerMWh
+}
+
+// WattTimeProvider implements CarbonDataProvider against WattTime's v3 API,
+// converting its MOER (marginal operating emissions rate) to gCO2/kWh.
+type WattTimeProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewWattTimeProvider creates a WattTime-backed provider. apiKey is the bearer
+// token obtained from WattTime's login endpoint.
+func NewWattTimeProvider(apiKey string) *WattTimeProvider {
+	return &WattTimeProvider{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type wattTimeIndexPoint struct {
+	PointTime time.Time `json:"point_time"`
+	Value     float64   `json:"value"`
+}
+
+type wattTimeIndexResponse struct {
+	Data []wattTimeIndexPoint `json:"data"`
+}
+
+func (p *WattTimeProvider) doRequest(path string, zone string, params map[string]string) (*wattTimeIndexResponse, error) {
+	url := fmt.Sprintf("https://api.watttime.org/v3/%s?region=%s", path, zone)
+	for k, v := range params {
+		url += fmt.Sprintf("&%s=%s", k, v)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("WattTime API request failed with status %d", resp.StatusCode)
+	}
+
+	var decoded wattTimeIndexResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode WattTime response: %w", err)
+	}
+	return &decoded, nil
+}
+
+func (p *WattTimeProvider) GetLatest(zone string) (*CarbonIntensityData, error) {
+	resp, err := p.doRequest("index", zone, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, fmt.Errorf("no index data returned for zone %s", zone)
+	}
+	point := resp.Data[0]
+	return &CarbonIntensityData{
+		Zone:            zone,
+		CarbonIntensity: int(wattTimeMOERToGramsPerKWh(point.Value)),
+		Datetime:        point.PointTime,
+		UpdatedAt:       point.PointTime,
+	}, nil
+}
+
+func (p *WattTimeProvider) GetRecent(zone string) ([]CarbonIntensityData, error) {
+	resp, err := p.doRequest("index", zone, map[string]string{"signal_type": "co2_moer"})
+	if err != nil {
+		return nil, err
+	}
+	data := make([]CarbonIntensityData, 0, len(resp.Data))
+	for _, point := range resp.Data {
+		data = append(data, CarbonIntensityData{
+			Zone:            zone,
+			CarbonIntensity: int(wattTimeMOERToGramsPerKWh(point.Value)),
+			Datetime:        point.PointTime,
+			UpdatedAt:       point.PointTime,
+		})
+	}
+	return data, nil
+}
+
+func (p *WattTimeProvider) GetForecast(zone string, hours int) ([]CarbonIntensityData, error) {
+	resp, err := p.doRequest("forecast", zone, map[string]string{"horizon_hours": strconv.Itoa(hours)})
+	if err != nil {
+		return nil, err
+	}
+	data := make([]CarbonIntensityData, 0, len(resp.Data))
+	for _, point := range resp.Data {
+		data = append(data, CarbonIntensityData{
+			Zone:            zone,
+			CarbonIntensity: int(wattTimeMOERToGramsPerKWh(point.Value)),
+			Datetime:        point.PointTime,
+			UpdatedAt:       point.PointTime,
+		})
+	}
+	return data, nil
+}
+
+func (p *WattTimeProvider) Name() string {
+	return "watttime"
+}
+
+// StaticCSVProvider loads a fixed zone -> carbon intensity mapping from a CSV
+// file (columns: zone,carbonIntensity) for air-gapped or testing use. It has
+// no concept of recency, so GetLatest/GetRecent/GetForecast all return the
+// same static value.
+type StaticCSVProvider struct {
+	intensities map[string]float64
+}
+
+// NewStaticCSVProvider loads a CSV of "zone,carbonIntensity" rows from path.
+func NewStaticCSVProvider(path string) (*StaticCSVProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open static CSV: %w", err)
+	}
+	defer f.Close()
+
+	intensities := make(map[string]float64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		if err != nil {
+			continue
+		}
+		intensities[strings.TrimSpace(fields[0])] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read static CSV: %w", err)
+	}
+
+	return &StaticCSVProvider{intensities: intensities}, nil
+}
+
+func (p *StaticCSVProvider) GetLatest(zone string) (*CarbonIntensityData, error) {
+	intensity, ok := p.intensities[zone]
+	if !ok {
+		return nil, fmt.Errorf("zone %s not present in static CSV data", zone)
+	}
+	now := time.Now()
+	return &CarbonIntensityData{
+		Zone:            zone,
+		CarbonIntensity: int(intensity),
+		Datetime:        now,
+		UpdatedAt:       now,
+	}, nil
+}
+
+func (p *StaticCSVProvider) GetRecent(zone string) ([]CarbonIntensityData, error) {
+	latest, err := p.GetLatest(zone)
+	if err != nil {
+		return nil, err
+	}
+	return []CarbonIntensityData{*latest}, nil
+}
+
+func (p *StaticCSVProvider) GetForecast(zone string, hours int) ([]CarbonIntensityData, error) {
+	latest, err := p.GetLatest(zone)
+	if err != nil {
+		return nil, err
+	}
+	// Static data has no forecast signal; repeat the fixed value for each hour.
+	forecast := make([]CarbonIntensityData, hours)
+	for i := range forecast {
+		point := *latest
+		point.Datetime = latest.Datetime.Add(time.Duration(i) * time.Hour)
+		forecast[i] = point
+	}
+	return forecast, nil
+}
+
+func (p *StaticCSVProvider) Name() string {
+	return "static-csv"
+}
+
+// MultiProvider falls through a list of providers on error, so unsupported
+// zones in one provider (e.g. a free static dataset) can be filled in by
+// another (e.g. live Electricity Maps coverage).
+type MultiProvider struct {
+	providers []CarbonDataProvider
+}
+
+// NewMultiProvider combines providers, consulted in order.
+func NewMultiProvider(providers ...CarbonDataProvider) *MultiProvider {
+	return &MultiProvider{providers: providers}
+}
+
+func (m *MultiProvider) GetLatest(zone string) (*CarbonIntensityData, error) {
+	var lastErr error
+	for _, p := range m.providers {
+		data, err := p.GetLatest(zone)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no provider could serve zone %s: %w", zone, lastErr)
+}
+
+func (m *MultiProvider) GetRecent(zone string) ([]CarbonIntensityData, error) {
+	var lastErr error
+	for _, p := range m.providers {
+		data, err := p.GetRecent(zone)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no provider could serve zone %s: %w", zone, lastErr)
+}
+
+func (m *MultiProvider) GetForecast(zone string, hours int) ([]CarbonIntensityData, error) {
+	var lastErr error
+	for _, p := range m.providers {
+		data, err := p.GetForecast(zone, hours)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no provider could serve zone %s: %w", zone, lastErr)
+}
+
+func (m *MultiProvider) Name() string {
+	return "multi"
+}
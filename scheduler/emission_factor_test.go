@@ -0,0 +1,71 @@
+package main
+
+import "testing"
+
+func TestRegionIntensityRespectsEmissionFactor(t *testing.T) {
+	region := Region{CarbonIntensity: 300, MOER: 450}
+
+	original := emissionFactor
+	defer func() { emissionFactor = original }()
+
+	tests := []struct {
+		name   string
+		factor EmissionFactor
+		want   float64
+	}{
+		{"average prefers CarbonIntensity", Average, 300},
+		{"marginal prefers MOER", MarginalOperating, 450},
+		{"lifecycle-average prefers CarbonIntensity", LifecycleAverage, 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emissionFactor = tt.factor
+			if got := regionIntensity(region); got != tt.want {
+				t.Errorf("regionIntensity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegionIntensityFallsBackWhenPreferredFieldMissing(t *testing.T) {
+	original := emissionFactor
+	defer func() { emissionFactor = original }()
+
+	emissionFactor = MarginalOperating
+	region := Region{CarbonIntensity: 300} // no MOER data
+	if got := regionIntensity(region); got != 300 {
+		t.Errorf("regionIntensity() with no MOER = %v, want fallback to CarbonIntensity (300)", got)
+	}
+
+	emissionFactor = Average
+	region = Region{MOER: 450} // no CarbonIntensity data
+	if got := regionIntensity(region); got != 450 {
+		t.Errorf("regionIntensity() with no CarbonIntensity = %v, want fallback to MOER (450)", got)
+	}
+}
+
+func TestGetAverageCarbonIntensityRejectsMarginalOperating(t *testing.T) {
+	client := &CarbonClient{}
+
+	if _, err := client.GetAverageCarbonIntensity("US-CAL-CISO", 1, MarginalOperating); err == nil {
+		t.Error("GetAverageCarbonIntensity(MarginalOperating) error = nil, want error (Electricity Maps doesn't serve marginal data)")
+	}
+}
+
+func TestEmissionFactorString(t *testing.T) {
+	tests := []struct {
+		factor EmissionFactor
+		want   string
+	}{
+		{Average, "average"},
+		{MarginalOperating, "marginal"},
+		{LifecycleAverage, "lifecycle-average"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.factor.String(); got != tt.want {
+			t.Errorf("%v.String() = %q, want %q", tt.factor, got, tt.want)
+		}
+	}
+}
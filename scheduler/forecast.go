@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/BU-KaveyZheng/CS476-Project/metrics"
+)
+
+// maxDelayAnnotation lets a pod opt into forecast-aware deferral: its value
+// is a duration string (e.g. "2h") bounding how long the pod may be delayed
+// past now in exchange for a greener forecasted start time.
+const maxDelayAnnotation = "carbon-aware.io/max-delay"
+
+// defaultPowerPerCoreWatts mirrors the simulator's default per-core power
+// draw, used to turn a carbon intensity delta into an estimated grams-saved
+// figure.
+const defaultPowerPerCoreWatts = 15.0
+
+// defaultAssumedRuntimeHours approximates a deferred pod's run length for the
+// grams-saved estimate. Unlike the simulator's synthetic jobs, the scheduler
+// has no actual runtime to work with at scheduling time, so this is a fixed
+// stand-in rather than a per-pod measurement.
+const defaultAssumedRuntimeHours = 1.0
+
+// deferralThresholdGrams is the minimum estimated grams of CO2 a deferral
+// must save over running now before a pod is worth delaying, configurable
+// via CARBON_DEFERRAL_THRESHOLD_GRAMS so noisy forecasts don't churn pods for
+// a marginal improvement.
+var deferralThresholdGrams = parseFloatEnvOrDefault("CARBON_DEFERRAL_THRESHOLD_GRAMS", 1.0)
+
+// deferralQueue holds the namespace/name keys of deferred pods, each added
+// back after a computed delay so the scheduler re-evaluates them once the
+// forecasted low-carbon window arrives.
+var deferralQueue = workqueue.NewDelayingQueue()
+
+func parseFloatEnvOrDefault(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		fmt.Printf("Warning: invalid float %q for %s, using default %.2f\n", value, key, fallback)
+		return fallback
+	}
+	return f
+}
+
+// podMaxDelay returns pod's requested deferral window and whether it opted
+// in via maxDelayAnnotation.
+func podMaxDelay(pod *corev1.Pod) (time.Duration, bool) {
+	value, ok := pod.Annotations[maxDelayAnnotation]
+	if !ok || value == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Printf("Pod %s/%s: invalid %s annotation %q: %v\n", pod.Namespace, pod.Name, maxDelayAnnotation, value, err)
+		return 0, false
+	}
+	return d, true
+}
+
+// forecastMinimum returns the lowest forecasted carbon intensity across every
+// region's Forecast points at or before window from now, plus the timestamp
+// it occurs at.
+func forecastMinimum(cache *CarbonCache, window time.Duration) (intensity float64, wakeTime time.Time, ok bool) {
+	now := time.Now()
+	deadline := now.Add(window)
+	first := true
+	for _, region := range cache.Regions {
+		for _, point := range region.Forecast {
+			ts, err := time.Parse(time.RFC3339, point.Timestamp)
+			if err != nil || ts.Before(now) || ts.After(deadline) {
+				continue
+			}
+			if first || point.CarbonIntensity < intensity {
+				intensity = point.CarbonIntensity
+				wakeTime = ts
+				first = false
+			}
+		}
+	}
+	return intensity, wakeTime, !first
+}
+
+// estimatedGrams estimates the CO2 emitted running a pod requesting podCPU
+// cores for defaultAssumedRuntimeHours at intensityGCO2PerKWh, mirroring the
+// simulator's CarbonIntensity * CPURequest * RuntimeHours * (PowerPerCore/1000)
+// energy-weighted formula.
+func estimatedGrams(podCPU resource.Quantity, intensityGCO2PerKWh float64) float64 {
+	cores := float64(podCPU.MilliValue()) / 1000.0
+	return intensityGCO2PerKWh * cores * defaultAssumedRuntimeHours * (defaultPowerPerCoreWatts / 1000.0)
+}
+
+// maybeDefer checks whether pod should be deferred to a greener forecasted
+// window rather than scheduled now: if it opted in via maxDelayAnnotation and
+// the minimum forecasted intensity within that window would save more than
+// deferralThresholdGrams of estimated emissions versus nowIntensity, pod's
+// key is pushed onto deferralQueue for re-delivery at the forecasted wake
+// time and maybeDefer returns true (the caller should leave pod unscheduled).
+func maybeDefer(pod *corev1.Pod, cache *CarbonCache, nowIntensity float64) bool {
+	window, ok := podMaxDelay(pod)
+	if !ok || window <= 0 {
+		return false
+	}
+
+	minIntensity, wakeTime, ok := forecastMinimum(cache, window)
+	if !ok || minIntensity >= nowIntensity {
+		return false
+	}
+
+	podCPU, _ := getPodResourceRequests(pod)
+	saved := estimatedGrams(podCPU, nowIntensity) - estimatedGrams(podCPU, minIntensity)
+	if saved <= deferralThresholdGrams {
+		return false
+	}
+
+	delay := time.Until(wakeTime)
+	if delay < 0 {
+		delay = 0
+	}
+	key := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	deferralQueue.AddAfter(key, delay)
+
+	metrics.SchedulerCarbonDeferredPods.Inc()
+	metrics.SchedulerCarbonGramsSavedTotal.Add(saved)
+	fmt.Printf("Pod %s/%s deferred: now=%.1f gCO2/kWh, forecasted min=%.1f gCO2/kWh at %s (in %s), estimated savings=%.2fg\n",
+		pod.Namespace, pod.Name, nowIntensity, minIntensity, wakeTime.Format(time.RFC3339), delay, saved)
+	return true
+}
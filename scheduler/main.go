@@ -2,9 +2,10 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -15,6 +16,9 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/BU-KaveyZheng/CS476-Project/metrics"
+	"github.com/BU-KaveyZheng/CS476-Project/pkg/carbon"
 )
 
 const (
@@ -28,8 +32,21 @@ const (
 var (
 	carbonAwareMode = os.Getenv("CARBON_AWARE_MODE") != "false" // Default to true
 	cacheFile       = getEnvOrDefault("CACHE_FILE", defaultCacheFile)
+	emissionFactor  = parseEmissionFactor(getEnvOrDefault("CARBON_EMISSION_FACTOR", "average"))
 )
 
+// parseEmissionFactor maps the CARBON_EMISSION_FACTOR env var to an EmissionFactor.
+func parseEmissionFactor(value string) EmissionFactor {
+	switch value {
+	case "marginal":
+		return MarginalOperating
+	case "lifecycle-average":
+		return LifecycleAverage
+	default:
+		return Average
+	}
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -53,13 +70,27 @@ func main() {
 		panic(err.Error())
 	}
 	fmt.Println("Connected to Kubernetes API")
-	
+
+	// Shared informer-backed cache: built once and reused across scheduling
+	// decisions instead of listing nodes/pods from the API on every pod.
+	schedCache := NewSchedulerCache(clientset)
+	fmt.Println("Scheduler cache synced")
+
 	if carbonAwareMode {
-		fmt.Printf("Carbon-aware scheduling ENABLED (cache: %s)\n", cacheFile)
+		fmt.Printf("Carbon-aware scheduling ENABLED (cache: %s, provider: %s)\n", cacheFile, getEnvOrDefault("CARBON_PROVIDER", "cache-file"))
 	} else {
 		fmt.Println("Carbon-aware scheduling DISABLED (non-carbon-aware mode)")
 	}
 
+	if metricsAddr := os.Getenv("METRICS_ADDR"); metricsAddr != "" {
+		go func() {
+			fmt.Printf("Serving Prometheus metrics on %s/metrics\n", metricsAddr)
+			if err := metrics.Serve(metricsAddr); err != nil {
+				fmt.Printf("Metrics server stopped: %v\n", err)
+			}
+		}()
+	}
+
 	// Watch for unscheduled pods
 	watchlist := cache.NewListWatchFromClient(
 		clientset.CoreV1().RESTClient(),
@@ -81,23 +112,7 @@ func main() {
 					return
 				}
 
-				fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-				fmt.Printf("Unscheduled pod detected: %s/%s\n", pod.Namespace, pod.Name)
-				podCPU, podMemory := getPodResourceRequests(pod)
-				fmt.Printf("Pod resource requests: CPU=%s Memory=%s\n", 
-					formatResource(podCPU), formatResource(podMemory))
-				nodeName := findBestNodeForPod(pod, clientset)
-				if nodeName != "" {
-					err := schedulePodToNode(pod, nodeName, clientset)
-					if err != nil {
-						fmt.Printf("❌ Failed to schedule pod %s to node %s: %v\n", pod.Name, nodeName, err)
-					} else {
-						fmt.Printf("✅ Pod %s scheduled to %s\n", pod.Name, nodeName)
-					}
-				} else {
-					fmt.Printf("❌ No suitable node found for pod %s\n", pod.Name)
-				}
-				fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+				handleUnscheduledPod(pod, schedCache, clientset)
 			},
 		},
 	)
@@ -105,11 +120,90 @@ func main() {
 	stop := make(chan struct{})
 	defer close(stop)
 	go controller.Run(stop)
+	go runDeferralQueue(stop, schedCache, clientset)
 
 	// Keep main thread alive
 	select {}
 }
 
+// handleUnscheduledPod runs the full scheduling pipeline for one unscheduled
+// pod: find a node (which may instead defer the pod to a greener forecasted
+// window), bind it, or fall back to preemption.
+func handleUnscheduledPod(pod *corev1.Pod, schedCache *SchedulerCache, clientset *kubernetes.Clientset) {
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("Unscheduled pod detected: %s/%s\n", pod.Namespace, pod.Name)
+	podCPU, podMemory := getPodResourceRequests(pod)
+	fmt.Printf("Pod resource requests: CPU=%s Memory=%s\n",
+		formatResource(podCPU), formatResource(podMemory))
+	nodeName, deferred := findBestNodeForPod(pod, schedCache)
+	if nodeName != "" {
+		err := schedulePodToNode(pod, nodeName, clientset)
+		if err != nil {
+			fmt.Printf("❌ Failed to schedule pod %s to node %s: %v\n", pod.Name, nodeName, err)
+		} else {
+			fmt.Printf("✅ Pod %s scheduled to %s\n", pod.Name, nodeName)
+		}
+	} else if deferred {
+		fmt.Printf("⏳ Pod %s deferred to a greener forecasted window\n", pod.Name)
+	} else {
+		fmt.Printf("❌ No suitable node found for pod %s, attempting preemption\n", pod.Name)
+		if nominated := Preempt(pod, schedCache, clientset); nominated != "" {
+			fmt.Printf("⏳ Pod %s nominated for node %s pending preemption\n", pod.Name, nominated)
+		} else {
+			fmt.Printf("❌ No suitable node found for pod %s, even with preemption\n", pod.Name)
+		}
+	}
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+}
+
+// runDeferralQueue drains deferralQueue (populated by maybeDefer) and
+// re-runs handleUnscheduledPod for each deferred pod once its forecasted
+// wake time arrives, so a pod deferred for being greener later actually gets
+// re-evaluated instead of staying unscheduled forever.
+func runDeferralQueue(stop <-chan struct{}, schedCache *SchedulerCache, clientset *kubernetes.Clientset) {
+	go func() {
+		<-stop
+		deferralQueue.ShutDown()
+	}()
+
+	for {
+		item, shutdown := deferralQueue.Get()
+		if shutdown {
+			return
+		}
+		key := item.(string)
+		deferralQueue.Done(key)
+
+		namespace, name, err := splitNamespacedKey(key)
+		if err != nil {
+			fmt.Printf("Deferral queue: invalid key %q: %v\n", key, err)
+			continue
+		}
+
+		pod, err := clientset.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			fmt.Printf("Deferral queue: pod %s no longer exists, dropping: %v\n", key, err)
+			continue
+		}
+		if pod.Spec.NodeName != "" {
+			continue // Already scheduled by another path
+		}
+
+		fmt.Printf("⏰ Deferred pod %s woke up, re-evaluating\n", key)
+		handleUnscheduledPod(pod, schedCache, clientset)
+	}
+}
+
+// splitNamespacedKey parses a "namespace/name" key as produced by maybeDefer.
+func splitNamespacedKey(key string) (namespace, name string, err error) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == '/' {
+			return key[:i], key[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("missing '/' separator")
+}
+
 // Carbon intensity cache structure
 type CarbonCache struct {
 	Timestamp   string            `json:"timestamp"`
@@ -131,49 +225,126 @@ type Region struct {
 	IsEstimated       bool    `json:"isEstimated"`
 	EstimationMethod  string  `json:"estimationMethod"`
 	Timestamp         string  `json:"timestamp"`
+	Forecast          []ForecastPoint `json:"forecast"` // Hourly forecast for the deferred-scheduling window
 }
 
-// Read carbon cache from file
+// ForecastPoint is one hourly forecasted carbon intensity sample for a
+// region, e.g. from Electricity Maps' forecast endpoint.
+type ForecastPoint struct {
+	Timestamp       string  `json:"timestamp"`
+	CarbonIntensity float64 `json:"carbonIntensity"`
+}
+
+// carbonForecastHorizon bounds how far into the future readCarbonCache asks
+// the carbon provider for forecasted intensity, wide enough to cover any
+// reasonable carbon-aware.io/max-delay a pod might request.
+const carbonForecastHorizon = 24 * time.Hour
+
+// carbonCacheRefreshInterval bounds how often readCarbonCache actually
+// queries the configured provider. A single scheduling cycle calls it once
+// per node scored (CarbonIntensityPriority) plus once per preemption
+// candidate (nodeCarbonIntensity); without memoizing within this window that
+// multiplies out to O(nodes) provider calls per pod, which is a live HTTP
+// round-trip per call against CARBON_PROVIDER=electricity-maps/watttime.
+const carbonCacheRefreshInterval = 30 * time.Second
+
+var (
+	carbonCacheMu       sync.Mutex
+	carbonCacheCached   *CarbonCache
+	carbonCacheCachedAt time.Time
+)
+
+// readCarbonCache returns the most recently fetched CarbonCache, reusing it
+// for up to carbonCacheRefreshInterval instead of re-querying the carbon
+// provider on every call.
 func readCarbonCache() (*CarbonCache, error) {
-	data, err := os.ReadFile(cacheFile)
+	carbonCacheMu.Lock()
+	defer carbonCacheMu.Unlock()
+
+	if carbonCacheCached != nil && time.Since(carbonCacheCachedAt) < carbonCacheRefreshInterval {
+		return carbonCacheCached, nil
+	}
+
+	cache, err := fetchCarbonCache()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read cache file: %w", err)
+		return nil, err
 	}
 
-	// First unmarshal into a generic map to check structure
-	var rawData map[string]interface{}
-	if err := json.Unmarshal(data, &rawData); err != nil {
-		return nil, fmt.Errorf("failed to parse cache: %w", err)
+	carbonCacheCached = cache
+	carbonCacheCachedAt = time.Now()
+	return cache, nil
+}
+
+// fetchCarbonCache asks the configured carbon.CarbonProvider (selected via
+// CARBON_PROVIDER, defaulting to the on-disk JSON cache at cacheFile) for
+// every region's current intensity and forecast, then assembles them into
+// the CarbonCache shape the rest of the scheduler already works with. This is
+// what decouples the scheduler from any one fetcher script or API - swapping
+// CARBON_PROVIDER swaps the data source without touching a scheduling
+// decision.
+func fetchCarbonCache() (*CarbonCache, error) {
+	provider, err := carbon.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load carbon provider: %w", err)
 	}
 
-	// Handle nested regions structure (backward compatibility fix)
-	if regionsRaw, ok := rawData["regions"].(map[string]interface{}); ok {
-		// Check if nested: regions.regions
-		if nestedRegions, ok := regionsRaw["regions"].(map[string]interface{}); ok {
-			// Flatten the structure
-			rawData["regions"] = nestedRegions
-		}
+	ctx := context.Background()
+	zones, err := provider.Regions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list carbon provider regions: %w", err)
 	}
 
-	// Now unmarshal into proper struct
-	var cache CarbonCache
-	cacheBytes, _ := json.Marshal(rawData)
-	if err := json.Unmarshal(cacheBytes, &cache); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal cache: %w", err)
+	now := time.Now().Format(time.RFC3339)
+	cache := &CarbonCache{
+		Timestamp: now,
+		Regions:   make(map[string]Region, len(zones)),
 	}
 
-	// Check if cache is expired
-	if cache.Timestamp != "" {
-		timestamp, err := time.Parse(time.RFC3339, cache.Timestamp)
-		if err == nil {
-			age := time.Since(timestamp)
-			if age > time.Duration(cache.TTLMinutes)*time.Minute {
-				return nil, fmt.Errorf("cache expired (age: %v)", age)
+	for _, zone := range zones {
+		intensity, err := provider.CurrentIntensity(ctx, zone)
+		if err != nil {
+			fmt.Printf("Warning: carbon provider could not serve region %s: %v\n", zone, err)
+			continue
+		}
+
+		region := Region{Zone: zone, CarbonIntensity: intensity, Timestamp: now}
+		if forecastPoints, err := provider.Forecast(ctx, zone, carbonForecastHorizon); err == nil {
+			for _, fp := range forecastPoints {
+				region.Forecast = append(region.Forecast, ForecastPoint{
+					Timestamp:       fp.Timestamp.Format(time.RFC3339),
+					CarbonIntensity: fp.CarbonIntensity,
+				})
 			}
 		}
+
+		cache.Regions[zone] = region
+		metrics.CarbonIntensity.WithLabelValues(zone).Set(intensity)
 	}
 
-	return &cache, nil
+	if len(cache.Regions) == 0 {
+		return nil, fmt.Errorf("carbon provider returned no usable regions")
+	}
+
+	cache.SortedByCarbon, cache.BestRegion, cache.WorstRegion = rankRegionsByCarbon(cache.Regions)
+	return cache, nil
+}
+
+// rankRegionsByCarbon sorts regions by ascending carbon intensity, used to
+// populate CarbonCache's best/worst/sorted fields regardless of which
+// provider supplied the data.
+func rankRegionsByCarbon(regions map[string]Region) (sorted []string, best, worst string) {
+	sorted = make([]string, 0, len(regions))
+	for zone := range regions {
+		sorted = append(sorted, zone)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return regions[sorted[i]].CarbonIntensity < regions[sorted[j]].CarbonIntensity
+	})
+	if len(sorted) > 0 {
+		best = sorted[0]
+		worst = sorted[len(sorted)-1]
+	}
+	return sorted, best, worst
 }
 
 // Get region for a node (from labels)
@@ -220,43 +391,8 @@ func formatResource(q resource.Quantity) string {
 	return q.String()
 }
 
-// Calculate allocated resources on a node (sum of all pods' requests)
-func getNodeAllocatedResources(nodeName string, clientset *kubernetes.Clientset) (cpu, memory resource.Quantity, err error) {
-	cpu = resource.Quantity{}
-	memory = resource.Quantity{}
-
-	// Get all pods on this node
-	pods, err := clientset.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{
-		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
-	})
-	if err != nil {
-		return cpu, memory, err
-	}
-
-	// Sum up resource requests from all pods
-	for _, pod := range pods.Items {
-		// Skip pods that are being deleted
-		if pod.DeletionTimestamp != nil {
-			continue
-		}
-
-		for _, container := range pod.Spec.Containers {
-			if req := container.Resources.Requests; req != nil {
-				if cpuReq, ok := req[corev1.ResourceCPU]; ok {
-					cpu.Add(cpuReq)
-				}
-				if memReq, ok := req[corev1.ResourceMemory]; ok {
-					memory.Add(memReq)
-				}
-			}
-		}
-	}
-
-	return cpu, memory, nil
-}
-
 // Check if node has enough resources for the pod
-func nodeHasResources(node *corev1.Node, pod *corev1.Pod, clientset *kubernetes.Clientset) bool {
+func nodeHasResources(node *corev1.Node, pod *corev1.Pod, cache *SchedulerCache) bool {
 	// Get pod resource requests
 	podCPU, podMemory := getPodResourceRequests(pod)
 
@@ -275,13 +411,8 @@ func nodeHasResources(node *corev1.Node, pod *corev1.Pod, clientset *kubernetes.
 		return false
 	}
 
-	// Get currently allocated resources on this node
-	allocatedCPU, allocatedMemory, err := getNodeAllocatedResources(node.Name, clientset)
-	if err != nil {
-		fmt.Printf("Warning: Could not get allocated resources for node %s: %v\n", node.Name, err)
-		// If we can't check, be conservative and skip this node
-		return false
-	}
+	// Get currently allocated resources on this node from the informer-backed cache
+	allocatedCPU, allocatedMemory := cache.NodeAllocatedResources(node.Name)
 
 	// Calculate available resources
 	availableCPU := nodeCPU.DeepCopy()
@@ -306,116 +437,88 @@ func nodeHasResources(node *corev1.Node, pod *corev1.Pod, clientset *kubernetes.
 	return true
 }
 
-// Find best node using carbon-aware scheduling
-func findBestNodeForPod(pod *corev1.Pod, clientset *kubernetes.Clientset) string {
-	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+// Find best node for pod using the Predicate/Priority pipeline from
+// scheduler_plugins.go: every node is first filtered by activePolicy's
+// predicates, then the survivors are scored by its weighted priorities and
+// the winner is the highest normalized (0-100) score. cache serves node
+// listing and per-node allocated resources from its informer-backed store
+// rather than issuing a List call per scheduling decision. If pod opts into
+// forecast-aware deferral (see maybeDefer) and deferring to a greener
+// forecasted window saves enough estimated emissions, findBestNodeForPod
+// leaves it unscheduled and returns ("", true).
+func findBestNodeForPod(pod *corev1.Pod, cache *SchedulerCache) (string, bool) {
+	nodes, err := cache.Nodes()
 	if err != nil {
 		fmt.Printf("Error listing nodes: %v\n", err)
-		return ""
+		return "", false
 	}
 
-	if len(nodes.Items) == 0 {
+	if len(nodes) == 0 {
 		fmt.Println("No nodes available for scheduling")
-		return ""
+		return "", false
 	}
 
+	predicates := activePredicates()
+
 	// Filter nodes that can run the pod
 	availableNodes := []corev1.Node{}
-	for _, node := range nodes.Items {
-		// Check if node is ready
-		isReady := false
-		for _, condition := range node.Status.Conditions {
-			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
-				isReady = true
-				break
-			}
-		}
-		if !isReady {
-			fmt.Printf("Node %s: not ready\n", node.Name)
-			continue
-		}
-
-		// Check node taints
-		canSchedule := true
-		for _, taint := range node.Spec.Taints {
-			if taint.Effect == corev1.TaintEffectNoSchedule {
-				canSchedule = false
-				fmt.Printf("Node %s: has NoSchedule taint\n", node.Name)
-				break
-			}
-		}
-		if !canSchedule {
+	for _, node := range nodes {
+		ok, reasons, err := RunPredicates(pod, &node, cache, predicates)
+		if err != nil {
+			fmt.Printf("Node %s: predicate error: %v\n", node.Name, err)
 			continue
 		}
-
-		// Check if node has enough resources
-		if !nodeHasResources(&node, pod, clientset) {
+		if !ok {
+			fmt.Printf("Node %s: failed predicates: %v\n", node.Name, reasons)
 			continue
 		}
 
 		availableNodes = append(availableNodes, node)
-		fmt.Printf("Node %s: available (passed all checks)\n", node.Name)
+		fmt.Printf("Node %s: available (passed all predicates)\n", node.Name)
 	}
 
 	if len(availableNodes) == 0 {
 		fmt.Println("No available nodes for scheduling")
-		return ""
+		return "", false
 	}
 
-	// Non-carbon-aware mode: return first available node
+	// Non-carbon-aware mode: return first available node, skipping the
+	// priority stage entirely.
 	if !carbonAwareMode {
 		fmt.Printf("Non-carbon-aware: scheduling to %s\n", availableNodes[0].Name)
-		return availableNodes[0].Name
+		metrics.PodsScheduledTotal.WithLabelValues("non-carbon-aware", getNodeRegion(&availableNodes[0])).Inc()
+		return availableNodes[0].Name, false
 	}
 
-	// Carbon-aware mode: read cache and select best node
-	cache, err := readCarbonCache()
-	if err != nil {
-		fmt.Printf("Warning: Could not read carbon cache (%v), falling back to first node\n", err)
-		return availableNodes[0].Name
-	}
-
-	fmt.Printf("Carbon cache loaded: %d regions, best: %s\n", len(cache.Regions), cache.BestRegion)
+	// Carbon-aware mode: score every available node with the policy's
+	// weighted priorities (see activePriorities) and take the best.
+	priorities := activePriorities()
 
-	// Score nodes based on carbon intensity
 	type nodeScore struct {
-		node  corev1.Node
-		score float64 // Lower is better (lower carbon intensity)
+		node   corev1.Node
+		score  int64 // Higher is better, normalized 0-100
 		region string
 	}
 
 	scores := []nodeScore{}
 	for _, node := range availableNodes {
-		region := getNodeRegion(&node)
-		score := float64(1000) // Default high score if region not found
-
-		if region != "" {
-			if regionData, ok := cache.Regions[region]; ok {
-				// Use carbonIntensity (Electricity Maps) or fall back to MOER (WattimeAPI)
-				if regionData.CarbonIntensity > 0 {
-					score = regionData.CarbonIntensity
-				} else if regionData.MOER > 0 {
-					score = regionData.MOER
-				}
-				fmt.Printf("Node %s: region=%s, Carbon Intensity=%.2f g CO2/kWh\n", node.Name, region, score)
-			} else {
-				fmt.Printf("Node %s: region=%s (not in cache)\n", node.Name, region)
-			}
-		} else {
-			fmt.Printf("Node %s: no region label found\n", node.Name)
+		score, err := RunPriorities(pod, &node, cache, priorities)
+		if err != nil {
+			fmt.Printf("Node %s: priority error: %v\n", node.Name, err)
+			continue
 		}
+		scores = append(scores, nodeScore{node: node, score: score, region: getNodeRegion(&node)})
+	}
 
-		scores = append(scores, nodeScore{
-			node:  node,
-			score: score,
-			region: region,
-		})
+	if len(scores) == 0 {
+		fmt.Println("No node produced a priority score")
+		return "", false
 	}
 
-	// Sort by score (lowest carbon intensity first)
+	// Highest normalized score wins
 	bestNode := scores[0]
 	for _, s := range scores {
-		if s.score < bestNode.score {
+		if s.score > bestNode.score {
 			bestNode = s
 		}
 	}
@@ -427,13 +530,25 @@ func findBestNodeForPod(pod *corev1.Pod, clientset *kubernetes.Clientset) string
 		if s.node.Name == bestNode.node.Name {
 			marker = "⭐"
 		}
-		fmt.Printf("  %s %s: region=%s, Carbon Intensity=%.2f g CO2/kWh\n", 
-			marker, s.node.Name, s.region, s.score)
+		fmt.Printf("  %s %s: region=%s, score=%d/100\n", marker, s.node.Name, s.region, s.score)
+	}
+
+	// Forecast-aware deferral: if pod opted into carbon-aware.io/max-delay and
+	// waiting for a forecasted greener window saves enough emissions, leave
+	// it unscheduled and requeue it for that wake time instead of binding now.
+	if carbonCache, err := readCarbonCache(); err == nil {
+		if regionData, ok := carbonCache.Regions[bestNode.region]; ok {
+			nowIntensity := regionIntensity(regionData)
+			if maybeDefer(pod, carbonCache, nowIntensity) {
+				return "", true
+			}
+		}
 	}
-	
-	fmt.Printf("\n✅ Carbon-aware decision: %s (region=%s, Carbon Intensity=%.2f g CO2/kWh)\n", 
+
+	fmt.Printf("\n✅ Carbon-aware decision: %s (region=%s, score=%d/100)\n",
 		bestNode.node.Name, bestNode.region, bestNode.score)
-	return bestNode.node.Name
+	metrics.PodsScheduledTotal.WithLabelValues("carbon-aware", bestNode.region).Inc()
+	return bestNode.node.Name, false
 }
 
 // Bind pod to the chosen node using proper Binding API
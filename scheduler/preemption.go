@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// preemptionEnabled toggles whether Preempt actually evicts victims
+// (PREEMPTION_ENABLED=true) or only dry-runs the selection and logs what it
+// would have evicted, mirroring carbonAwareMode's env-var convention.
+var preemptionEnabled = os.Getenv("PREEMPTION_ENABLED") == "true"
+
+// preemptionCandidate is one node considered by Preempt: the victims whose
+// eviction would free enough room for the pending pod, and the node's region
+// carbon intensity (used to prefer greener eviction targets).
+type preemptionCandidate struct {
+	node            corev1.Node
+	victims         []corev1.Pod
+	carbonIntensity float64
+}
+
+// podPriority returns pod's scheduling priority, defaulting to 0 (matching
+// Kubernetes' own default) when unset.
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
+// nodeCarbonIntensity resolves node's region carbon intensity for ranking
+// preemption candidates, falling back to the highest possible value when
+// unknown so a node with no carbon data is never preferred over one with data.
+func nodeCarbonIntensity(node *corev1.Node) float64 {
+	region := getNodeRegion(node)
+	if region == "" {
+		return math.MaxFloat64
+	}
+	cache, err := readCarbonCache()
+	if err != nil {
+		return math.MaxFloat64
+	}
+	regionData, ok := cache.Regions[region]
+	if !ok {
+		return math.MaxFloat64
+	}
+	return regionIntensity(regionData)
+}
+
+// minimalEvictionSet returns the smallest set of node's lower-priority pods
+// (lowest-priority first) whose eviction would free enough CPU/memory for
+// pod, or ok=false if node could never fit pod even with every evictable pod
+// removed.
+func minimalEvictionSet(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (victims []corev1.Pod, ok bool) {
+	podCPU, podMemory := getPodResourceRequests(pod)
+
+	nodeCPU, hasCPU := node.Status.Allocatable[corev1.ResourceCPU]
+	nodeMemory, hasMemory := node.Status.Allocatable[corev1.ResourceMemory]
+	if !hasCPU || !hasMemory || nodeCPU.Cmp(podCPU) < 0 || nodeMemory.Cmp(podMemory) < 0 {
+		return nil, false
+	}
+
+	pods, err := cache.PodsOnNode(node.Name)
+	if err != nil {
+		fmt.Printf("Warning: could not list pods on node %s for preemption: %v\n", node.Name, err)
+		return nil, false
+	}
+
+	var evictable []corev1.Pod
+	for _, candidate := range pods {
+		if candidate.DeletionTimestamp != nil {
+			continue
+		}
+		if podPriority(candidate) >= podPriority(pod) {
+			continue
+		}
+		evictable = append(evictable, *candidate)
+	}
+	sort.Slice(evictable, func(i, j int) bool {
+		return podPriority(&evictable[i]) < podPriority(&evictable[j])
+	})
+
+	allocatedCPU, allocatedMemory := cache.NodeAllocatedResources(node.Name)
+	availableCPU := nodeCPU.DeepCopy()
+	availableCPU.Sub(allocatedCPU)
+	availableMemory := nodeMemory.DeepCopy()
+	availableMemory.Sub(allocatedMemory)
+
+	var chosen []corev1.Pod
+	for availableCPU.Cmp(podCPU) < 0 || availableMemory.Cmp(podMemory) < 0 {
+		if len(evictable) == 0 {
+			return nil, false
+		}
+		victim := evictable[0]
+		evictable = evictable[1:]
+
+		victimCPU, victimMemory := getPodResourceRequests(&victim)
+		availableCPU.Add(victimCPU)
+		availableMemory.Add(victimMemory)
+		chosen = append(chosen, victim)
+	}
+
+	return chosen, true
+}
+
+// Preempt looks for a node where evicting one or more lower-priority pods
+// would free enough resources for pod, mirroring kube-scheduler's classic
+// ScheduleAlgorithm.Preempt: only pods with a strictly lower pod.Spec.Priority
+// than the pending pod are ever considered, the minimum eviction set is
+// computed per candidate node, and - when more than one candidate works - the
+// node in the lowest-carbon region is preferred, so preemption steers work
+// toward sustainability even under resource pressure. Victims are evicted
+// through the policy/v1 Eviction API so PodDisruptionBudgets are respected.
+// If PREEMPTION_ENABLED is not "true", victims are only selected and logged
+// (dry-run), never actually evicted. Returns the name of the node nominated
+// for pod (and sets its status.nominatedNodeName), or "" if no preemption
+// could help.
+func Preempt(pod *corev1.Pod, cache *SchedulerCache, clientset *kubernetes.Clientset) string {
+	if pod.Spec.Priority == nil {
+		fmt.Printf("Pod %s has no priority set; cannot preempt on its behalf\n", pod.Name)
+		return ""
+	}
+
+	nodes, err := cache.Nodes()
+	if err != nil {
+		fmt.Printf("Error listing nodes for preemption: %v\n", err)
+		return ""
+	}
+
+	var candidates []preemptionCandidate
+	for _, node := range nodes {
+		victims, ok := minimalEvictionSet(pod, &node, cache)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, preemptionCandidate{
+			node:            node,
+			victims:         victims,
+			carbonIntensity: nodeCarbonIntensity(&node),
+		})
+	}
+
+	if len(candidates) == 0 {
+		fmt.Printf("No preemption candidate found for pod %s\n", pod.Name)
+		return ""
+	}
+
+	// Prefer the candidate in the lowest-carbon region; ties broken by fewest
+	// victims, so preemption disturbs the least work to achieve the same result.
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].carbonIntensity != candidates[j].carbonIntensity {
+			return candidates[i].carbonIntensity < candidates[j].carbonIntensity
+		}
+		return len(candidates[i].victims) < len(candidates[j].victims)
+	})
+	chosen := candidates[0]
+
+	for i := range chosen.victims {
+		evictPod(&chosen.victims[i], clientset, pod)
+	}
+
+	if err := setNominatedNodeName(pod, chosen.node.Name, clientset); err != nil {
+		fmt.Printf("Warning: failed to set nominatedNodeName for pod %s: %v\n", pod.Name, err)
+	}
+
+	fmt.Printf("Preemption: nominated node %s for pod %s, evicting %d victim(s)\n", chosen.node.Name, pod.Name, len(chosen.victims))
+	return chosen.node.Name
+}
+
+// evictPod evicts victim through the policy/v1 Eviction API, so any
+// PodDisruptionBudget protecting it is honored, and emits a Kubernetes Event
+// recording why. In dry-run (preemptionEnabled == false) the eviction is only
+// logged and evented, never submitted to the API.
+func evictPod(victim *corev1.Pod, clientset *kubernetes.Clientset, preemptor *corev1.Pod) {
+	reason := fmt.Sprintf("Preempted by higher-priority carbon-aware pod %s/%s", preemptor.Namespace, preemptor.Name)
+
+	if !preemptionEnabled {
+		fmt.Printf("[dry-run] would evict pod %s/%s: %s\n", victim.Namespace, victim.Name, reason)
+		emitPreemptionEvent(clientset, victim, reason, true)
+		return
+	}
+
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      victim.Name,
+			Namespace: victim.Namespace,
+		},
+	}
+	if err := clientset.PolicyV1().Evictions(victim.Namespace).Evict(context.Background(), eviction); err != nil {
+		fmt.Printf("Failed to evict pod %s/%s: %v\n", victim.Namespace, victim.Name, err)
+		return
+	}
+	fmt.Printf("Evicted pod %s/%s: %s\n", victim.Namespace, victim.Name, reason)
+	emitPreemptionEvent(clientset, victim, reason, false)
+}
+
+// emitPreemptionEvent records a Kubernetes Event on victim explaining why it
+// was (or, in dry-run, would be) evicted for preemption.
+func emitPreemptionEvent(clientset *kubernetes.Clientset, victim *corev1.Pod, reason string, dryRun bool) {
+	action := "Preempted"
+	message := reason
+	if dryRun {
+		action = "PreemptionDryRun"
+		message = "[dry-run] " + reason
+	}
+
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "carbon-preemption-",
+			Namespace:    victim.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      victim.Name,
+			Namespace: victim.Namespace,
+			UID:       victim.UID,
+		},
+		Reason:         action,
+		Message:        message,
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: schedulerName},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := clientset.CoreV1().Events(victim.Namespace).Create(context.Background(), event, metav1.CreateOptions{}); err != nil {
+		fmt.Printf("Warning: failed to emit preemption event for pod %s/%s: %v\n", victim.Namespace, victim.Name, err)
+	}
+}
+
+// setNominatedNodeName patches pod's status.nominatedNodeName to nodeName,
+// the same signal kube-scheduler uses to tell other components (and this
+// scheduler's next cycle) which node preemption cleared room on.
+func setNominatedNodeName(pod *corev1.Pod, nodeName string, clientset *kubernetes.Clientset) error {
+	patch := []byte(fmt.Sprintf(`{"status":{"nominatedNodeName":%q}}`, nodeName))
+	_, err := clientset.CoreV1().Pods(pod.Namespace).Patch(context.Background(), pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newTestSchedulerCache builds a SchedulerCache backed by a plain indexer
+// (no informer factory or API connection), so minimalEvictionSet can be
+// exercised against a fixed set of pods. It also seeds the allocated-resource
+// usage map the way addPod would, since minimalEvictionSet reads both the
+// per-node pod index and the per-node resource totals.
+func newTestSchedulerCache(pods ...*corev1.Pod) *SchedulerCache {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		nodeNameIndex: func(obj interface{}) ([]string, error) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok || pod.Spec.NodeName == "" {
+				return nil, nil
+			}
+			return []string{pod.Spec.NodeName}, nil
+		},
+	})
+	sc := &SchedulerCache{
+		podIndexer: indexer,
+		usage:      make(map[string]*nodeResourceUsage),
+	}
+	for _, pod := range pods {
+		_ = indexer.Add(pod)
+		sc.addPod(pod)
+	}
+	return sc
+}
+
+func testPod(name, nodeName string, priority int32, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Priority: &priority,
+			Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+	}
+}
+
+func testNode(name, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func TestMinimalEvictionSetEvictsLowestPriorityFirst(t *testing.T) {
+	low := testPod("low", "node-1", 1, "1", "1Gi")
+	mid := testPod("mid", "node-1", 2, "1", "1Gi")
+	node := testNode("node-1", "2", "2Gi")
+	cache := newTestSchedulerCache(low, mid)
+
+	pending := testPod("pending", "", 5, "1", "1Gi")
+
+	victims, ok := minimalEvictionSet(pending, node, cache)
+	if !ok {
+		t.Fatalf("minimalEvictionSet() ok = false, want true")
+	}
+	if len(victims) != 1 || victims[0].Name != "low" {
+		t.Fatalf("minimalEvictionSet() victims = %v, want [low]", victims)
+	}
+}
+
+func TestMinimalEvictionSetSkipsHigherOrEqualPriority(t *testing.T) {
+	peer := testPod("peer", "node-1", 5, "2", "2Gi")
+	node := testNode("node-1", "2", "2Gi")
+	cache := newTestSchedulerCache(peer)
+
+	pending := testPod("pending", "", 5, "1", "1Gi")
+
+	_, ok := minimalEvictionSet(pending, node, cache)
+	if ok {
+		t.Fatalf("minimalEvictionSet() ok = true, want false (peer is not strictly lower priority)")
+	}
+}
+
+func TestMinimalEvictionSetNodeTooSmall(t *testing.T) {
+	node := testNode("node-1", "1", "1Gi")
+	cache := newTestSchedulerCache()
+
+	pending := testPod("pending", "", 5, "2", "2Gi")
+
+	_, ok := minimalEvictionSet(pending, node, cache)
+	if ok {
+		t.Fatalf("minimalEvictionSet() ok = true, want false (node allocatable below pod request)")
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// nodeResourceUsage is the running total of resource requests of every
+// non-terminal pod currently bound to one node.
+type nodeResourceUsage struct {
+	cpu    resource.Quantity
+	memory resource.Quantity
+}
+
+// nodeNameIndex indexes the pod informer's store by spec.nodeName, so
+// PodsOnNode can serve from the local cache instead of a live
+// Pods().List(FieldSelector: "spec.nodeName=...") call.
+const nodeNameIndex = "nodeName"
+
+// SchedulerCache is the node/pod context predicates and priorities need
+// beyond the single (pod, node) pair being evaluated. It's backed by a shared
+// informer factory: Nodes() serves from the node informer's local store
+// instead of an API list call, per-node allocated resources are kept up to
+// date incrementally by the pod informer's Add/Update/Delete handlers, and
+// PodsOnNode serves from the pod informer's nodeNameIndex - so a scheduling
+// decision costs O(nodes) in-memory lookups rather than O(pods) API calls.
+type SchedulerCache struct {
+	clientset *kubernetes.Clientset
+
+	nodeLister corelisters.NodeLister
+	podIndexer cache.Indexer
+
+	mu    sync.RWMutex
+	usage map[string]*nodeResourceUsage // node name -> summed requests of pods bound to it
+}
+
+// NewSchedulerCache builds a SchedulerCache backed by a shared informer
+// factory for clientset, starts the factory, and blocks until the node and
+// pod informers have completed their initial list-and-watch sync.
+func NewSchedulerCache(clientset *kubernetes.Clientset) *SchedulerCache {
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	nodeInformer := factory.Core().V1().Nodes()
+	podInformer := factory.Core().V1().Pods()
+
+	sc := &SchedulerCache{
+		clientset:  clientset,
+		nodeLister: nodeInformer.Lister(),
+		podIndexer: podInformer.Informer().GetIndexer(),
+		usage:      make(map[string]*nodeResourceUsage),
+	}
+
+	podInformer.Informer().AddIndexers(cache.Indexers{
+		nodeNameIndex: func(obj interface{}) ([]string, error) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok || pod.Spec.NodeName == "" {
+				return nil, nil
+			}
+			return []string{pod.Spec.NodeName}, nil
+		},
+	})
+
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				sc.addPod(pod)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if oldPod, ok := oldObj.(*corev1.Pod); ok {
+				sc.removePod(oldPod)
+			}
+			if newPod, ok := newObj.(*corev1.Pod); ok {
+				sc.addPod(newPod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					return
+				}
+				pod, ok = tombstone.Obj.(*corev1.Pod)
+				if !ok {
+					return
+				}
+			}
+			sc.removePod(pod)
+		},
+	})
+
+	stopCh := make(chan struct{})
+	factory.Start(stopCh)
+	for informerType, synced := range factory.WaitForCacheSync(stopCh) {
+		if !synced {
+			fmt.Printf("Warning: informer %v failed to sync\n", informerType)
+		}
+	}
+
+	return sc
+}
+
+// addPod adds pod's resource requests to its node's running total, unless
+// pod isn't bound yet, is terminal, or is already being deleted - mirroring
+// the DeletionTimestamp skip the old per-call List-based sum applied.
+func (sc *SchedulerCache) addPod(pod *corev1.Pod) {
+	if pod.Spec.NodeName == "" || podIsTerminal(pod) || pod.DeletionTimestamp != nil {
+		return
+	}
+	cpu, memory := getPodResourceRequests(pod)
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	nodeUsage, ok := sc.usage[pod.Spec.NodeName]
+	if !ok {
+		nodeUsage = &nodeResourceUsage{}
+		sc.usage[pod.Spec.NodeName] = nodeUsage
+	}
+	nodeUsage.cpu.Add(cpu)
+	nodeUsage.memory.Add(memory)
+}
+
+// removePod reverses addPod, subtracting pod's resource requests from its
+// node's running total. It skips the same terminal/already-deleting pods
+// addPod does: those never had their requests added back in by a later
+// addPod call, so a pod that went terminal via an Update (which already
+// subtracted it) must not be subtracted again when its Delete event follows.
+func (sc *SchedulerCache) removePod(pod *corev1.Pod) {
+	if pod.Spec.NodeName == "" || podIsTerminal(pod) || pod.DeletionTimestamp != nil {
+		return
+	}
+	cpu, memory := getPodResourceRequests(pod)
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	nodeUsage, ok := sc.usage[pod.Spec.NodeName]
+	if !ok {
+		return
+	}
+	nodeUsage.cpu.Sub(cpu)
+	nodeUsage.memory.Sub(memory)
+}
+
+// podIsTerminal reports whether pod has finished running, so it no longer
+// holds its resource requests against its node.
+func podIsTerminal(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// Nodes returns every node currently known to the informer cache.
+func (sc *SchedulerCache) Nodes() ([]corev1.Node, error) {
+	nodes, err := sc.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]corev1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, *node)
+	}
+	return result, nil
+}
+
+// NodeAllocatedResources returns nodeName's summed pod resource requests from
+// the in-memory cache maintained by the pod informer's event handlers,
+// instead of a live Pods().List() call.
+func (sc *SchedulerCache) NodeAllocatedResources(nodeName string) (cpu, memory resource.Quantity) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	nodeUsage, ok := sc.usage[nodeName]
+	if !ok {
+		return resource.Quantity{}, resource.Quantity{}
+	}
+	return nodeUsage.cpu.DeepCopy(), nodeUsage.memory.DeepCopy()
+}
+
+// PodsOnNode returns every pod currently bound to nodeName, served from the
+// pod informer's nodeNameIndex instead of a live Pods().List() call.
+func (sc *SchedulerCache) PodsOnNode(nodeName string) ([]*corev1.Pod, error) {
+	objs, err := sc.podIndexer.ByIndex(nodeNameIndex, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*corev1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
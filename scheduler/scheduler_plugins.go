@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxPriorityScore is the ceiling a single PriorityFunc can award a node,
+// mirroring kube-scheduler's classic 0-10 priority scale.
+const maxPriorityScore = 10
+
+// FitPredicate reports whether node is eligible to run pod, modeled on
+// kube-scheduler's classic FitPredicate signature. A false result carries
+// human-readable reasons for logging why the node was rejected.
+type FitPredicate func(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (bool, []string, error)
+
+// PriorityFunc scores node's fitness for pod on a 0-maxPriorityScore scale,
+// where higher is better, modeled on kube-scheduler's classic PriorityFunc
+// signature.
+type PriorityFunc func(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (int64, error)
+
+// NodeReady rejects nodes that aren't reporting corev1.NodeReady=True.
+func NodeReady(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (bool, []string, error) {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			return true, nil, nil
+		}
+	}
+	return false, []string{"node not ready"}, nil
+}
+
+// NoTaint rejects nodes carrying a NoSchedule taint. Toleration matching
+// isn't modeled since no pod scheduled by this scheduler sets Tolerations today.
+func NoTaint(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (bool, []string, error) {
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule {
+			return false, []string{"has NoSchedule taint"}, nil
+		}
+	}
+	return true, nil, nil
+}
+
+// PodFitsResources rejects nodes without enough allocatable CPU/memory left
+// for pod's resource requests, accounting for every other pod already bound
+// to the node.
+func PodFitsResources(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (bool, []string, error) {
+	if nodeHasResources(node, pod, cache) {
+		return true, nil, nil
+	}
+	return false, []string{"insufficient cpu/memory"}, nil
+}
+
+// regionIntensity resolves a Region's carbon intensity according to the
+// configured emissionFactor convention (see main.go's parseEmissionFactor),
+// since marginal operating emissions (WattTime's MOER) and average emissions
+// (Electricity Maps) can invert which region looks greenest.
+func regionIntensity(region Region) float64 {
+	switch emissionFactor {
+	case MarginalOperating:
+		if region.MOER > 0 {
+			return region.MOER
+		}
+		return region.CarbonIntensity
+	default: // Average, LifecycleAverage
+		if region.CarbonIntensity > 0 {
+			return region.CarbonIntensity
+		}
+		return region.MOER
+	}
+}
+
+// CarbonIntensityPriority scores node by how green its region's carbon
+// intensity ranks against every other region in this cycle's carbon cache,
+// so the greenest known region wins the full maxPriorityScore. Falls back to
+// a neutral mid-score when node's region is missing or not in the cache, so
+// an incomplete cache degrades gracefully instead of zeroing a node's chances.
+func CarbonIntensityPriority(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (int64, error) {
+	region := getNodeRegion(node)
+	if region == "" {
+		return maxPriorityScore / 2, nil
+	}
+
+	carbonCache, err := readCarbonCache()
+	if err != nil {
+		return maxPriorityScore / 2, nil
+	}
+
+	regionData, ok := carbonCache.Regions[region]
+	if !ok {
+		return maxPriorityScore / 2, nil
+	}
+	intensity := regionIntensity(regionData)
+
+	minIntensity, maxIntensity := intensity, intensity
+	for _, r := range carbonCache.Regions {
+		v := regionIntensity(r)
+		if v < minIntensity {
+			minIntensity = v
+		}
+		if v > maxIntensity {
+			maxIntensity = v
+		}
+	}
+	if maxIntensity <= minIntensity {
+		return maxPriorityScore, nil
+	}
+
+	fraction := (maxIntensity - intensity) / (maxIntensity - minIntensity)
+	return int64(fraction * maxPriorityScore), nil
+}
+
+// leastRequestedScore implements kube-scheduler's least_requested formula for
+// a single resource: (capacity-requested)*maxPriorityScore/capacity.
+func leastRequestedScore(capacity, requested int64) int64 {
+	if capacity <= 0 || requested > capacity {
+		return 0
+	}
+	return ((capacity - requested) * maxPriorityScore) / capacity
+}
+
+// LeastRequestedPriority favors nodes with more unused capacity after pod is
+// placed, averaging the least_requested score across CPU and memory.
+func LeastRequestedPriority(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (int64, error) {
+	podCPU, podMemory := getPodResourceRequests(pod)
+
+	nodeCPU, ok := node.Status.Allocatable[corev1.ResourceCPU]
+	if !ok {
+		return 0, fmt.Errorf("node %s has no allocatable cpu", node.Name)
+	}
+	nodeMemory, ok := node.Status.Allocatable[corev1.ResourceMemory]
+	if !ok {
+		return 0, fmt.Errorf("node %s has no allocatable memory", node.Name)
+	}
+
+	allocatedCPU, allocatedMemory := cache.NodeAllocatedResources(node.Name)
+
+	cpuScore := leastRequestedScore(nodeCPU.MilliValue(), allocatedCPU.MilliValue()+podCPU.MilliValue())
+	memScore := leastRequestedScore(nodeMemory.Value(), allocatedMemory.Value()+podMemory.Value())
+	return (cpuScore + memScore) / 2, nil
+}
+
+// resourceFraction returns requested/capacity as a 0-1+ fraction.
+func resourceFraction(capacity, requested int64) float64 {
+	if capacity <= 0 {
+		return 1
+	}
+	return float64(requested) / float64(capacity)
+}
+
+// BalancedResourceAllocation favors nodes whose CPU and memory utilization
+// fractions (after placing pod) are close to each other, following
+// kube-scheduler's balanced_resource_allocation formula, so the cluster
+// doesn't end up CPU-starved on some nodes and memory-starved on others.
+func BalancedResourceAllocation(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache) (int64, error) {
+	podCPU, podMemory := getPodResourceRequests(pod)
+
+	nodeCPU, ok := node.Status.Allocatable[corev1.ResourceCPU]
+	if !ok {
+		return 0, fmt.Errorf("node %s has no allocatable cpu", node.Name)
+	}
+	nodeMemory, ok := node.Status.Allocatable[corev1.ResourceMemory]
+	if !ok {
+		return 0, fmt.Errorf("node %s has no allocatable memory", node.Name)
+	}
+
+	allocatedCPU, allocatedMemory := cache.NodeAllocatedResources(node.Name)
+
+	cpuFraction := resourceFraction(nodeCPU.MilliValue(), allocatedCPU.MilliValue()+podCPU.MilliValue())
+	memFraction := resourceFraction(nodeMemory.Value(), allocatedMemory.Value()+podMemory.Value())
+	if cpuFraction > 1 || memFraction > 1 {
+		return 0, nil
+	}
+
+	diff := cpuFraction - memFraction
+	if diff < 0 {
+		diff = -diff
+	}
+	return int64((1.0 - diff) * maxPriorityScore), nil
+}
+
+// predicateRegistry maps a predicate name (as used in the policy ConfigMap)
+// to its FitPredicate. RegisterPredicate lets callers add custom predicates
+// beyond the built-ins below.
+var predicateRegistry = map[string]FitPredicate{
+	"NodeReady":        NodeReady,
+	"NoTaint":          NoTaint,
+	"PodFitsResources": PodFitsResources,
+}
+
+// priorityRegistry maps a priority name to its PriorityFunc.
+var priorityRegistry = map[string]PriorityFunc{
+	"CarbonIntensityPriority":    CarbonIntensityPriority,
+	"LeastRequestedPriority":     LeastRequestedPriority,
+	"BalancedResourceAllocation": BalancedResourceAllocation,
+}
+
+// RegisterPredicate adds or overrides a named predicate in the registry.
+func RegisterPredicate(name string, predicate FitPredicate) {
+	predicateRegistry[name] = predicate
+}
+
+// RegisterPriority adds or overrides a named priority in the registry.
+func RegisterPriority(name string, priority PriorityFunc) {
+	priorityRegistry[name] = priority
+}
+
+// WeightedPriority pairs a registered PriorityFunc with the weight its score
+// is multiplied by before being summed into a node's total priority score.
+type WeightedPriority struct {
+	Name   string
+	Func   PriorityFunc
+	Weight int64
+}
+
+// RunPredicates evaluates every predicate for (pod, node) in order,
+// short-circuiting on the first failure and returning its reasons.
+func RunPredicates(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache, predicates []FitPredicate) (bool, []string, error) {
+	for _, predicate := range predicates {
+		ok, reasons, err := predicate(pod, node, cache)
+		if err != nil {
+			return false, nil, err
+		}
+		if !ok {
+			return false, reasons, nil
+		}
+	}
+	return true, nil, nil
+}
+
+// RunPriorities evaluates every weighted priority for (pod, node) and
+// returns the weighted sum normalized to 0-100, where 100 means every
+// priority awarded its maximum score (maxPriorityScore) on this node.
+func RunPriorities(pod *corev1.Pod, node *corev1.Node, cache *SchedulerCache, priorities []WeightedPriority) (int64, error) {
+	var total, maxTotal int64
+	for _, wp := range priorities {
+		score, err := wp.Func(pod, node, cache)
+		if err != nil {
+			return 0, fmt.Errorf("priority %q failed: %w", wp.Name, err)
+		}
+		total += score * wp.Weight
+		maxTotal += maxPriorityScore * wp.Weight
+	}
+	if maxTotal == 0 {
+		return 0, nil
+	}
+	return total * 100 / maxTotal, nil
+}
+
+// PriorityWeightConfig is the on-disk schema for one entry in the policy
+// ConfigMap's weighted priority list.
+type PriorityWeightConfig struct {
+	Name   string `json:"name"`
+	Weight int64  `json:"weight"`
+}
+
+// SchedulerPolicyConfig is the on-disk schema for the scheduler's policy
+// ConfigMap, mounted at policyFile: which predicates gate eligibility, and
+// how each priority's weight blends into the final normalized score. e.g.:
+//
+//	{
+//	  "predicates": ["NodeReady", "NoTaint", "PodFitsResources"],
+//	  "priorities": [
+//	    {"name": "CarbonIntensityPriority", "weight": 3},
+//	    {"name": "LeastRequestedPriority", "weight": 1},
+//	    {"name": "BalancedResourceAllocation", "weight": 1}
+//	  ]
+//	}
+type SchedulerPolicyConfig struct {
+	Predicates []string               `json:"predicates"`
+	Priorities []PriorityWeightConfig `json:"priorities"`
+}
+
+// defaultPolicyFile is where the scheduler's policy ConfigMap is mounted in
+// cluster, mirroring cacheFile's PVC-mount convention.
+const defaultPolicyFile = "/policy/scheduler_policy.json"
+
+var policyFile = getEnvOrDefault("POLICY_FILE", defaultPolicyFile)
+
+// defaultPredicateNames/defaultPriorityWeights preserve today's behavior
+// (ready + no-taint + resource-fit gating, pure carbon-intensity scoring)
+// when no policy ConfigMap is mounted.
+var defaultPredicateNames = []string{"NodeReady", "NoTaint", "PodFitsResources"}
+
+var defaultPriorityWeights = []PriorityWeightConfig{
+	{Name: "CarbonIntensityPriority", Weight: 1},
+}
+
+// activePolicy holds the predicates/priorities each scheduling cycle runs:
+// the policy ConfigMap's contents if one is mounted at policyFile, else the
+// defaults above.
+var activePolicy = loadSchedulerPolicy()
+
+func loadSchedulerPolicy() SchedulerPolicyConfig {
+	data, err := os.ReadFile(policyFile)
+	if err != nil {
+		return SchedulerPolicyConfig{Predicates: defaultPredicateNames, Priorities: defaultPriorityWeights}
+	}
+	var cfg SchedulerPolicyConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		fmt.Printf("Warning: failed to parse policy ConfigMap %s: %v, using defaults\n", policyFile, err)
+		return SchedulerPolicyConfig{Predicates: defaultPredicateNames, Priorities: defaultPriorityWeights}
+	}
+	if len(cfg.Predicates) == 0 {
+		cfg.Predicates = defaultPredicateNames
+	}
+	if len(cfg.Priorities) == 0 {
+		cfg.Priorities = defaultPriorityWeights
+	}
+	return cfg
+}
+
+// activePredicates resolves activePolicy's predicate names against the
+// registry, skipping (with a warning) any name that isn't registered.
+func activePredicates() []FitPredicate {
+	predicates := make([]FitPredicate, 0, len(activePolicy.Predicates))
+	for _, name := range activePolicy.Predicates {
+		predicate, ok := predicateRegistry[name]
+		if !ok {
+			fmt.Printf("Warning: unknown predicate %q in policy, skipping\n", name)
+			continue
+		}
+		predicates = append(predicates, predicate)
+	}
+	return predicates
+}
+
+// activePriorities resolves activePolicy's weighted priority names against
+// the registry, skipping (with a warning) any name that isn't registered.
+func activePriorities() []WeightedPriority {
+	priorities := make([]WeightedPriority, 0, len(activePolicy.Priorities))
+	for _, wc := range activePolicy.Priorities {
+		fn, ok := priorityRegistry[wc.Name]
+		if !ok {
+			fmt.Printf("Warning: unknown priority %q in policy, skipping\n", wc.Name)
+			continue
+		}
+		priorities = append(priorities, WeightedPriority{Name: wc.Name, Func: fn, Weight: wc.Weight})
+	}
+	return priorities
+}
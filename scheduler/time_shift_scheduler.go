@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeShiftScheduler picks the future start time within a pod's deadline whose
+// sliding window has the lowest average forecast carbon intensity, following the
+// "shift workload to the greenest hour" pattern used by carbon-aware schedulers.
+type TimeShiftScheduler struct {
+	carbonClient *CarbonClient
+}
+
+// NewTimeShiftScheduler creates a scheduler that consults carbonClient's forecast
+// endpoint to time-shift deferrable workloads.
+func NewTimeShiftScheduler(carbonClient *CarbonClient) *TimeShiftScheduler {
+	return &TimeShiftScheduler{carbonClient: carbonClient}
+}
+
+// TimeShiftPlan describes when a deferrable workload should start and why.
+type TimeShiftPlan struct {
+	Zone             string
+	StartTime        time.Time
+	WindowAvgCarbon  float64
+	DelayFromNow     time.Duration
+}
+
+// PlanStartTime returns the start time, within [now, now+deadline-runtime], whose
+// runtime-length window has the lowest average forecast carbon intensity for zone.
+// If no forecast data is available, it falls back to scheduling immediately.
+func (s *TimeShiftScheduler) PlanStartTime(zone string, runtime time.Duration, deadline time.Duration) (*TimeShiftPlan, error) {
+	if runtime <= 0 {
+		return nil, fmt.Errorf("runtime must be positive")
+	}
+	if deadline < runtime {
+		return nil, fmt.Errorf("deadline %s is shorter than runtime %s", deadline, runtime)
+	}
+
+	horizonHours := int(deadline.Hours()) + 1
+	forecast, err := s.carbonClient.GetCarbonIntensityForecast(zone, horizonHours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get carbon forecast: %w", err)
+	}
+	if len(forecast) == 0 {
+		now := time.Now()
+		return &TimeShiftPlan{Zone: zone, StartTime: now, DelayFromNow: 0}, nil
+	}
+
+	windowHours := int(runtime.Hours())
+	if windowHours < 1 {
+		windowHours = 1
+	}
+
+	best := -1
+	bestAvg := 0.0
+	for start := 0; start+windowHours <= len(forecast); start++ {
+		total := 0.0
+		for i := start; i < start+windowHours; i++ {
+			total += float64(forecast[i].CarbonIntensity)
+		}
+		avg := total / float64(windowHours)
+		if best == -1 || avg < bestAvg {
+			best = start
+			bestAvg = avg
+		}
+	}
+
+	if best == -1 {
+		// Forecast shorter than the requested window; use the earliest point available.
+		best = 0
+		bestAvg = float64(forecast[0].CarbonIntensity)
+	}
+
+	startTime := forecast[best].Datetime
+	return &TimeShiftPlan{
+		Zone:            zone,
+		StartTime:       startTime,
+		WindowAvgCarbon: bestAvg,
+		DelayFromNow:    time.Until(startTime),
+	}, nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ScorerWeights holds the user-supplied weights for each scoring dimension.
+// Higher CarbonWeight/CostWeight/LatencyWeight penalize a region more; higher
+// CapacityWeight rewards regions with more free capacity.
+type ScorerWeights struct {
+	Carbon   float64
+	Cost     float64
+	Latency  float64
+	Capacity float64
+}
+
+// Scorer computes a weighted multi-objective score per region, combining
+// normalized carbon intensity, spot price, cross-region latency, and free
+// capacity fraction. Lower scores are better.
+type Scorer struct {
+	weights ScorerWeights
+
+	minCarbon, maxCarbon   float64
+	minPrice, maxPrice     float64
+	minLatency, maxLatency float64
+}
+
+// RegionLatency maps a region to its estimated cross-region latency in
+// milliseconds relative to the scheduling origin; regions absent from the map
+// are assumed to have zero added latency (e.g. same-region scheduling).
+type RegionLatency map[string]float64
+
+// NewScorer builds a Scorer whose normalization ranges are derived from the
+// given cache and latency map, so that weights are comparable across runs with
+// different underlying carbon/price magnitudes.
+func NewScorer(weights ScorerWeights, cache *CarbonCache, latencies RegionLatency) *Scorer {
+	s := &Scorer{weights: weights, minCarbon: math.MaxFloat64, minPrice: math.MaxFloat64, minLatency: math.MaxFloat64}
+
+	for _, data := range cache.Regions {
+		carbon := data.CarbonIntensity
+		if carbon == 0 {
+			carbon = data.MOER
+		}
+		s.minCarbon = math.Min(s.minCarbon, carbon)
+		s.maxCarbon = math.Max(s.maxCarbon, carbon)
+		s.minPrice = math.Min(s.minPrice, data.SpotPricePerHour)
+		s.maxPrice = math.Max(s.maxPrice, data.SpotPricePerHour)
+	}
+	for _, latency := range latencies {
+		s.minLatency = math.Min(s.minLatency, latency)
+		s.maxLatency = math.Max(s.maxLatency, latency)
+	}
+	if s.minLatency == math.MaxFloat64 {
+		s.minLatency, s.maxLatency = 0, 0
+	}
+
+	return s
+}
+
+// normalize maps value into [0, 1] given the observed [min, max] range,
+// returning 0 when the range is degenerate (min == max).
+func normalize(value, min, max float64) float64 {
+	if max <= min {
+		return 0
+	}
+	return (value - min) / (max - min)
+}
+
+// Score computes score(region) = w_carbon*normCarbon + w_cost*normPrice +
+// w_latency*normLatency - w_capacity*freeFraction. Lower is better.
+func (s *Scorer) Score(region string, data Region, latencies RegionLatency, used int) float64 {
+	carbon := data.CarbonIntensity
+	if carbon == 0 {
+		carbon = data.MOER
+	}
+
+	normCarbon := normalize(carbon, s.minCarbon, s.maxCarbon)
+	normPrice := normalize(data.SpotPricePerHour, s.minPrice, s.maxPrice)
+	normLatency := normalize(latencies[region], s.minLatency, s.maxLatency)
+
+	freeFraction := 1.0
+	if data.Capacity > 0 {
+		freeFraction = float64(data.Capacity-used) / float64(data.Capacity)
+		if freeFraction < 0 {
+			freeFraction = 0
+		}
+	}
+
+	return s.weights.Carbon*normCarbon +
+		s.weights.Cost*normPrice +
+		s.weights.Latency*normLatency -
+		s.weights.Capacity*freeFraction
+}
+
+// WeightedBinPackResult is the outcome of greedily packing pods into regions by
+// ascending score until each region's capacity is exhausted.
+type WeightedBinPackResult struct {
+	Assignments map[string]int
+	TotalCarbonGrams float64
+	TotalCostUSD     float64
+}
+
+// binPackByScore greedily assigns pods to the lowest-scoring region with
+// remaining capacity, re-scoring after each placement since free capacity
+// fraction changes as regions fill up. Regions without an explicit Capacity
+// are treated as having unlimited room.
+func binPackByScore(cache *CarbonCache, scorer *Scorer, latencies RegionLatency, numPods int, podDurationHours, energyPerPodKWh float64) WeightedBinPackResult {
+	result := WeightedBinPackResult{Assignments: make(map[string]int)}
+	used := make(map[string]int)
+
+	for i := 0; i < numPods; i++ {
+		bestRegion := ""
+		bestScore := math.MaxFloat64
+		for region, data := range cache.Regions {
+			if data.Capacity > 0 && used[region] >= data.Capacity {
+				continue
+			}
+			score := scorer.Score(region, data, latencies, used[region])
+			if score < bestScore {
+				bestScore = score
+				bestRegion = region
+			}
+		}
+		if bestRegion == "" {
+			break // all regions exhausted their capacity
+		}
+
+		used[bestRegion]++
+		result.Assignments[bestRegion]++
+
+		data := cache.Regions[bestRegion]
+		carbon := data.CarbonIntensity
+		if carbon == 0 {
+			carbon = data.MOER
+		}
+		result.TotalCarbonGrams += carbon * energyPerPodKWh
+		result.TotalCostUSD += data.SpotPricePerHour * podDurationHours
+	}
+
+	return result
+}
+
+// printWeightedComparison runs the weighted multi-objective bin-packer alongside
+// pure-carbon (all pods to the single best region) and round-robin baselines,
+// and reports the Pareto trade-off between grams of CO2 saved and dollars spent.
+func printWeightedComparison(cache *CarbonCache, numPods int, energyPerPodKWh float64) {
+	const podDurationHours = 1.0
+	weights := ScorerWeights{Carbon: 0.6, Cost: 0.2, Latency: 0.1, Capacity: 0.1}
+	latencies := RegionLatency{} // no cross-region latency data available offline; treat as uniform
+
+	scorer := NewScorer(weights, cache, latencies)
+	weighted := binPackByScore(cache, scorer, latencies, numPods, podDurationHours, energyPerPodKWh)
+
+	pureCarbon := simulateCarbonAware(cache, numPods, energyPerPodKWh)
+	pureCarbonCost := 0.0
+	for region, count := range pureCarbon.RegionAssignments {
+		pureCarbonCost += cache.Regions[region].SpotPricePerHour * podDurationHours * float64(count)
+	}
+
+	roundRobin := simulateNonCarbonAware(cache, numPods, energyPerPodKWh)
+	roundRobinCost := 0.0
+	for region, count := range roundRobin.RegionAssignments {
+		roundRobinCost += cache.Regions[region].SpotPricePerHour * podDurationHours * float64(count)
+	}
+
+	fmt.Println("\nWeighted Multi-Objective Comparison (carbon + cost + latency + capacity):")
+	fmt.Printf("%-20s | %-18s | %-15s\n", "Mode", "Total Carbon (g)", "Total Cost ($)")
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("%-20s | %18.2f | %15.2f\n", "Weighted", weighted.TotalCarbonGrams, weighted.TotalCostUSD)
+	fmt.Printf("%-20s | %18.2f | %15.2f\n", "Pure-Carbon", pureCarbon.TotalCarbonGrams, pureCarbonCost)
+	fmt.Printf("%-20s | %18.2f | %15.2f\n", "Round-Robin", roundRobin.TotalCarbonGrams, roundRobinCost)
+
+	gramsDelta := weighted.TotalCarbonGrams - pureCarbon.TotalCarbonGrams
+	dollarsDelta := weighted.TotalCostUSD - pureCarbonCost
+	fmt.Printf("\nPareto trade-off (weighted vs. pure-carbon): %+.2f g CO2, %+.2f USD\n", gramsDelta, dollarsDelta)
+}
@@ -1,8 +1,12 @@
+// Command basic is the original round-robin/carbon-aware/time-shift/weighted
+// comparison CLI, kept as its own binary since it predates (and doesn't
+// share types with) the discrete-event simulator in simulator/enhanced_simulate.go.
 package main
 
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"strings"
 	"time"
@@ -17,6 +21,8 @@ type SimulationResult struct {
 	NodeAssignments   map[string]int
 	RegionAssignments map[string]int
 	CarbonByRegion    map[string]float64
+	BestRegionUsed      string  // set by time-shift mode: region pods were ultimately sent to
+	AverageDelayMinutes float64 // set by time-shift mode: average temporal shift applied per pod
 }
 
 // Carbon cache structure (same as scheduler)
@@ -40,6 +46,8 @@ type Region struct {
 	IsEstimated       bool    `json:"isEstimated"`
 	EstimationMethod  string  `json:"estimationMethod"`
 	Timestamp         string  `json:"timestamp"`
+	Capacity          int     `json:"capacity"`         // Max pods this region can accept
+	SpotPricePerHour  float64 `json:"spotPricePerHour"` // USD per pod-hour in this region
 }
 
 // Simulated pod with estimated energy consumption
@@ -53,19 +61,26 @@ type SimulatedPod struct {
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: simulate <cache_file> [num_pods] [energy_per_pod_kwh]")
+		fmt.Println("Usage: simulate <cache_file> [num_pods] [energy_per_pod_kwh] [--mode=time-shift]")
 		fmt.Println("Example: simulate /tmp/carbon_cache.json 100 0.5")
+		fmt.Println("Example: simulate /tmp/carbon_cache.json 100 0.5 --mode=time-shift")
 		os.Exit(1)
 	}
 
 	cacheFile := os.Args[1]
 	numPods := 100
 	energyPerPodKWh := 0.5
+	mode := "standard"
 
-	if len(os.Args) >= 3 {
+	for _, arg := range os.Args[2:] {
+		if strings.HasPrefix(arg, "--mode=") {
+			mode = strings.TrimPrefix(arg, "--mode=")
+		}
+	}
+	if len(os.Args) >= 3 && !strings.HasPrefix(os.Args[2], "--mode=") {
 		fmt.Sscanf(os.Args[2], "%d", &numPods)
 	}
-	if len(os.Args) >= 4 {
+	if len(os.Args) >= 4 && !strings.HasPrefix(os.Args[3], "--mode=") {
 		fmt.Sscanf(os.Args[3], "%f", &energyPerPodKWh)
 	}
 
@@ -84,7 +99,16 @@ func main() {
 	nonCarbonAware := simulateNonCarbonAware(cache, numPods, energyPerPodKWh)
 
 	// Simulate carbon-aware scheduling
-	carbonAware := simulateCarbonAware(cache, numPods, energyPerPodKWh)
+	var carbonAware SimulationResult
+	switch mode {
+	case "time-shift":
+		carbonAware = simulateTimeShift(cache, numPods, energyPerPodKWh)
+	case "weighted":
+		carbonAware = simulateCarbonAware(cache, numPods, energyPerPodKWh)
+		printWeightedComparison(cache, numPods, energyPerPodKWh)
+	default:
+		carbonAware = simulateCarbonAware(cache, numPods, energyPerPodKWh)
+	}
 
 	// Print results
 	printResults(nonCarbonAware, carbonAware)
@@ -96,6 +120,51 @@ func main() {
 	fmt.Println("\n" + strings.Repeat("=", 62))
 	fmt.Printf("CARBON SAVINGS: %.2f g CO2 (%.2f%% reduction)\n", savings, savingsPercent)
 	fmt.Println(strings.Repeat("=", 62))
+
+	if mode == "time-shift" {
+		fmt.Printf("  spatial (region) component: scheduled to %s\n", carbonAware.BestRegionUsed)
+		fmt.Printf("  temporal (delay) component: average delay of %.1f minutes per pod\n", carbonAware.AverageDelayMinutes)
+	}
+
+	printEmissionFactorComparison(cache, numPods, energyPerPodKWh)
+}
+
+// emissionFactorSavings re-runs the carbon-aware savings calculation using the
+// given field selector (average CarbonIntensity vs. marginal MOER), since the
+// two conventions can invert which region looks "best".
+func emissionFactorSavings(cache *CarbonCache, numPods int, energyPerPodKWh float64, useMarginal bool) (bestRegion string, carbonGrams float64) {
+	bestRegion = ""
+	minCarbon := math.MaxFloat64
+	for region, data := range cache.Regions {
+		value := data.CarbonIntensity
+		if useMarginal {
+			value = data.MOER
+		}
+		if value > 0 && value < minCarbon {
+			minCarbon = value
+			bestRegion = region
+		}
+	}
+	if bestRegion == "" {
+		return "", 0
+	}
+	return bestRegion, minCarbon * energyPerPodKWh * float64(numPods)
+}
+
+// printEmissionFactorComparison prints carbon-aware savings side-by-side for the
+// average and marginal operating emission-factor conventions.
+func printEmissionFactorComparison(cache *CarbonCache, numPods int, energyPerPodKWh float64) {
+	avgRegion, avgCarbon := emissionFactorSavings(cache, numPods, energyPerPodKWh, false)
+	marginalRegion, marginalCarbon := emissionFactorSavings(cache, numPods, energyPerPodKWh, true)
+
+	fmt.Println("\nEmission Factor Comparison:")
+	fmt.Printf("%-25s | %-20s | %-20s\n", "Factor", "Best Region", "Total Carbon (g CO2)")
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-25s | %-20s | %20.2f\n", "Average (Electricity Maps)", avgRegion, avgCarbon)
+	fmt.Printf("%-25s | %-20s | %20.2f\n", "Marginal (WattTime MOER)", marginalRegion, marginalCarbon)
+	if avgRegion != "" && marginalRegion != "" && avgRegion != marginalRegion {
+		fmt.Println("Note: average and marginal factors selected different best regions.")
+	}
 }
 
 func readCarbonCache(cacheFile string) (*CarbonCache, error) {
@@ -226,6 +295,85 @@ func simulateCarbonAware(cache *CarbonCache, numPods int, energyPerPodKWh float6
 	return result
 }
 
+// forecastWindow returns a synthetic hourly carbon-intensity forecast for a region,
+// modeling the typical midday solar dip as a diurnal multiplier on the region's
+// current intensity. This stands in for a real forecast API response until the
+// simulator gains its own carbon data client.
+func forecastWindow(baseIntensity float64, hours int) []float64 {
+	forecast := make([]float64, hours)
+	for h := 0; h < hours; h++ {
+		// Diurnal curve: carbon dips toward the middle of the window (more solar),
+		// rises at the edges (more fossil generation).
+		phase := float64(h%24) / 24.0
+		multiplier := 1.0 - 0.3*math.Sin(phase*math.Pi)
+		forecast[h] = baseIntensity * multiplier
+	}
+	return forecast
+}
+
+// bestWindowStart finds the hour offset whose `windowHours`-long sliding window has
+// the lowest average forecast carbon intensity, returning that offset and the average.
+func bestWindowStart(forecast []float64, windowHours int) (int, float64) {
+	if windowHours < 1 {
+		windowHours = 1
+	}
+	bestStart, bestAvg := 0, math.MaxFloat64
+	for start := 0; start+windowHours <= len(forecast); start++ {
+		total := 0.0
+		for i := start; i < start+windowHours; i++ {
+			total += forecast[i]
+		}
+		avg := total / float64(windowHours)
+		if avg < bestAvg {
+			bestStart, bestAvg = start, avg
+		}
+	}
+	return bestStart, bestAvg
+}
+
+// simulateTimeShift schedules pods by shifting their start time into the greenest
+// forecast window for the best region, rather than always running immediately.
+// It reports both the spatial (region choice) and temporal (delay) contribution
+// to carbon savings versus round-robin scheduling.
+func simulateTimeShift(cache *CarbonCache, numPods int, energyPerPodKWh float64) SimulationResult {
+	result := SimulationResult{
+		Mode:              "Time-Shift",
+		TotalPods:         numPods,
+		NodeAssignments:   make(map[string]int),
+		RegionAssignments: make(map[string]int),
+		CarbonByRegion:    make(map[string]float64),
+	}
+
+	bestRegion := cache.BestRegion
+	if bestRegion == "" {
+		for region := range cache.Regions {
+			bestRegion = region
+			break
+		}
+	}
+	regionData := cache.Regions[bestRegion]
+	baseIntensity := regionData.CarbonIntensity
+	if baseIntensity == 0 {
+		baseIntensity = regionData.MOER
+	}
+
+	const deadlineHours = 6
+	const podRuntimeHours = 1
+	forecast := forecastWindow(baseIntensity, deadlineHours)
+	windowStart, windowAvg := bestWindowStart(forecast, podRuntimeHours)
+
+	carbonPerPod := energyPerPodKWh * windowAvg
+	result.RegionAssignments[bestRegion] = numPods
+	result.NodeAssignments[fmt.Sprintf("node-%s-0", bestRegion)] = numPods
+	result.CarbonByRegion[bestRegion] = carbonPerPod * float64(numPods)
+	result.TotalCarbonGrams = carbonPerPod * float64(numPods)
+	result.AverageCarbonPerPod = carbonPerPod
+	result.BestRegionUsed = bestRegion
+	result.AverageDelayMinutes = float64(windowStart) * 60.0
+
+	return result
+}
+
 func printResults(nonCarbonAware, carbonAware SimulationResult) {
 	fmt.Printf("\n%-30s | %-30s\n", "Non-Carbon-Aware", "Carbon-Aware")
 	fmt.Println(strings.Repeat("-", 65))
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestDominantShare(t *testing.T) {
+	capacity := ClusterCapacity{TotalCPU: 100, TotalMemory: 200}
+
+	tests := []struct {
+		name  string
+		state *TenantState
+		want  float64
+	}{
+		{
+			name:  "nil state",
+			state: nil,
+			want:  0,
+		},
+		{
+			name:  "cpu-dominant",
+			state: &TenantState{CPUUsed: 40, MemoryUsed: 20},
+			want:  0.4, // 40/100 > 20/200
+		},
+		{
+			name:  "memory-dominant",
+			state: &TenantState{CPUUsed: 10, MemoryUsed: 100},
+			want:  0.5, // 100/200 > 10/100
+		},
+		{
+			name:  "equal shares",
+			state: &TenantState{CPUUsed: 50, MemoryUsed: 100},
+			want:  0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dominantShare(tt.state, capacity); got != tt.want {
+				t.Errorf("dominantShare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDominantShareZeroCapacity(t *testing.T) {
+	state := &TenantState{CPUUsed: 10, MemoryUsed: 10}
+
+	if got := dominantShare(state, ClusterCapacity{TotalCPU: 0, TotalMemory: 100}); got != 0 {
+		t.Errorf("dominantShare() with zero TotalCPU = %v, want 0", got)
+	}
+	if got := dominantShare(state, ClusterCapacity{TotalCPU: 100, TotalMemory: 0}); got != 0 {
+		t.Errorf("dominantShare() with zero TotalMemory = %v, want 0", got)
+	}
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"container/heap"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"math"
 	"math/rand"
 	"os"
+	"runtime"
+	"runtime/pprof"
 	"sort"
 	"time"
+
+	"github.com/BU-KaveyZheng/CS476-Project/metrics"
 )
 
 // Enhanced simulation with comprehensive metrics
@@ -63,8 +69,25 @@ type Job struct {
 	Latency         time.Duration // Time from creation to start (queue + scheduling)
 	TurnaroundTime  time.Duration // Time from creation to completion
 	ExecutionTime   time.Duration // Actual execution time
+	Tenant          string        // Owning tenant/queue, used by DominantResourceFairness
+	Tolerations     []string      // Taints this job tolerates, checked by TaintTolerationFilter
+	AllowedRegions  []string      // If non-empty, restricts placement to these regions (RegionAffinityFilter)
+	Priority        int               // Higher wins eviction contests; used by CarbonAwarePreemptive
+	PreemptionPolicy PreemptionPolicy // Whether this job may itself be evicted to make room for a higher-priority job
+	PoolPreference  string   // Pool this job is softly pinned to; eligible for cross-pool borrowing if that pool is saturated
+	AllowedPools    []string // If non-empty, hard-restricts placement to these pools (no borrowing)
+	NotBefore       time.Time // Under CarbonAwareDeferred, the earliest time this queued job may start
 }
 
+// PreemptionPolicy controls whether a job may be evicted from a node to make
+// room for a higher-priority arrival.
+type PreemptionPolicy int
+
+const (
+	Preemptible  PreemptionPolicy = iota // May be evicted by a higher-priority job (default)
+	NeverPreempt                         // Never evicted, regardless of priority comparison
+)
+
 // Node represents a compute node
 type Node struct {
 	Name            string
@@ -79,6 +102,11 @@ type Node struct {
 	Queue           []*Job // Jobs waiting in queue
 	Utilization     float64 // CPU utilization percentage
 	QueueLength     int     // Current queue length
+	Taints          []string // Taints this node carries; a job must tolerate all of them to be placed here
+	Pool            string   // Capacity pool this node belongs to (e.g. "on-demand", "spot", "gpu")
+	Forecast        []float64 // Hourly carbon-intensity forecast; Forecast[0] is now, Forecast[i] is i hours out
+	PowerPerCore    float64   // Watts drawn per CPU core under load, used for energy-weighted carbon accounting
+	SubCluster      string    // Named grouping below region (e.g. "us-east-1/gpu"), used for hotspot reporting
 }
 
 // SchedulerType represents different scheduling strategies
@@ -90,6 +118,9 @@ const (
 	Random
 	LeastLoaded
 	HighestCarbon // Worst case for comparison
+	DominantResourceFairness // Equalizes tenants' dominant share of cluster CPU/memory
+	CarbonAwarePreemptive    // CarbonAware placement that may evict lower-priority jobs to avoid queueing
+	CarbonAwareDeferred      // May delay a job's start, within MaxDeferral, for a lower-carbon forecasted window
 )
 
 // SimulationConfig holds simulation parameters
@@ -105,6 +136,60 @@ type SimulationConfig struct {
 	ComputeJobRatio  float64 // Ratio of compute-intensive jobs (0.0-1.0)
 	NumNodes         int
 	Regions          []string
+	NumTenants       int // Number of tenants/queues jobs are distributed across
+	ProtectedFractionOfFairShare float64 // Tenants at or below this fraction of their fair share are never preempted; 1.0 = never preempt below fair share
+	CrossPoolBorrowThreshold     float64 // A soft-pinned job may borrow into another pool once its home pool's utilization exceeds this fraction (0-1)
+	CrossPoolBorrowCarbonCeiling float64 // A borrow target pool is only eligible if its average carbon intensity is at or below this ceiling
+	MaxDeferral      time.Duration // CarbonAwareDeferred may delay a job's start by up to this long for a lower-carbon forecasted window
+	ForecastTopN     int           // Number of lowest-scoring (node, startTime) candidates CarbonAwareDeferred keeps while searching
+	UtilizationSampleInterval time.Duration // How often (in simulated time) the UtilizationGatherer samples node CPU/memory
+	UtilizationBufferSize     int           // Max samples retained per node's ring buffer before the oldest is dropped
+}
+
+// TenantState tracks a tenant's resource consumption for Dominant Resource
+// Fairness: the sum of CPU and memory currently held by the tenant's running
+// jobs, normalized against cluster capacity to find the tenant's dominant share.
+type TenantState struct {
+	CPUUsed       float64
+	MemoryUsed    float64
+	JobsServed    int
+	CO2Attributed float64
+}
+
+// ClusterCapacity is the total CPU/memory available across all nodes, used to
+// normalize a tenant's resource usage into a dominant share.
+type ClusterCapacity struct {
+	TotalCPU    float64
+	TotalMemory float64
+}
+
+// CarbonForecast is an hourly carbon-intensity time series for one region,
+// used by CarbonAwareDeferred to look ahead before committing a job's start
+// time. Hourly[0] is the current intensity; Hourly[i] is i hours from now.
+type CarbonForecast struct {
+	Zone   string    `json:"zone"`
+	Hourly []float64 `json:"hourly"`
+}
+
+// dominantShare returns the tenant's dominant share: the larger of its
+// normalized CPU and memory usage across the cluster.
+func dominantShare(state *TenantState, capacity ClusterCapacity) float64 {
+	if state == nil || capacity.TotalCPU == 0 || capacity.TotalMemory == 0 {
+		return 0
+	}
+	cpuShare := state.CPUUsed / capacity.TotalCPU
+	memShare := state.MemoryUsed / capacity.TotalMemory
+	if cpuShare > memShare {
+		return cpuShare
+	}
+	return memShare
+}
+
+// TenantStats summarizes one tenant's outcome for a single scheduler run.
+type TenantStats struct {
+	DominantShare float64
+	JobsServed    int
+	CO2Attributed float64
 }
 
 // SimulationResults holds comprehensive metrics
@@ -115,6 +200,7 @@ type SimulationResults struct {
 	FailedJobs           int
 	AverageCarbon        float64
 	TotalCarbon          float64
+	P95Carbon            float64 // P95 carbon intensity across all completed jobs, from the carbon intensity histogram
 	AverageLatency       time.Duration
 	AverageTurnaround    time.Duration
 	P95Latency           time.Duration
@@ -127,11 +213,73 @@ type SimulationResults struct {
 	Throughput           float64 // Jobs per hour
 	CarbonReduction      float64 // Percentage vs worst case
 	LatencyReduction     float64 // Percentage vs worst case
+	TenantStats          map[string]TenantStats // Per-tenant dominant share / jobs served / CO2 attributed
+	PreemptedJobs             int     // Jobs evicted mid-run to make room for a higher-priority arrival
+	WastedCPUHours            float64 // CPU-hours lost to preempted jobs' incomplete execution
+	CarbonWastedOnPreemption  float64 // Sum of carbon intensity attributed to preempted jobs' discarded work
+	PoolStats                 map[string]PoolStats // Per-pool utilization/carbon/queue-depth/throughput
+	CrossPoolBorrowCount      int                  // Jobs placed outside their home pool via cross-pool borrowing
+	DeferredJobs              int     // Jobs whose start was delayed for a lower-carbon forecasted window
+	AverageDeferralMinutes    float64 // Average delay among deferred jobs
+	CarbonSavedByDeferral     float64 // Sum of (carbon now - carbon at deferred start) * duration hours, across deferred jobs
+	NodeUtilizationP95        map[string]float64  // Per-node P95 CPU utilization across the whole run
+	NodeMemP95                map[string]float64  // Per-node P95 memory utilization across the whole run
+	ClusterUtilSeries         UtilizationSummary  // Cluster-wide CPU utilization mean/percentiles/peak across the whole run
+	CarbonByRegionP95         map[string]float64  // Per-region P95 carbon intensity, from the carbon intensity histogram
+	TotalCO2Grams             float64             // Energy-weighted total CO2 emitted: sum of CarbonIntensity * CPURequest * RuntimeHours * (PowerPerCore/1000) across completed jobs
+	CoreHours                 float64             // Sum of CPURequest * RuntimeHours across completed jobs
+	NodeHours                 float64             // Sum of RuntimeHours across completed jobs (one node occupied per job for its runtime)
+	AvgCarbonPerCoreHour      float64             // TotalCO2Grams / CoreHours; the energy-weighted carbon rate, comparable across schedulers regardless of job mix
+	CO2GramsByRegion          map[string]float64  // Energy-weighted CO2 grams per region
+	CoreHoursByRegion         map[string]float64  // Core-hours per region, denominator for the region's energy-weighted average
+	SubClusterStats           map[string]SubClusterStats // Per-sub-cluster (region/pool grouping) jobs/carbon/utilization/P95 latency, for the HOTSPOTS report
+	RegionStats               map[string]RegionStats     // Per-region jobs/carbon/utilization/latency, for the HOTSPOTS report
+}
+
+// SubClusterStats summarizes one sub-cluster's (see subClusterName) outcome:
+// jobs served, average carbon intensity, mean CPU utilization across its
+// member nodes, P95 latency, and throughput — the fields the HOTSPOTS report
+// can rank by via --rank-by.
+type SubClusterStats struct {
+	Jobs        int
+	AvgCarbon   float64
+	Utilization float64
+	P95Latency  time.Duration
+	Throughput  float64 // Jobs per hour
+}
+
+// RegionStats mirrors SubClusterStats at region granularity, letting the
+// HOTSPOTS report rank plain regions by the same metrics as sub-clusters.
+type RegionStats struct {
+	Jobs        int
+	AvgCarbon   float64
+	Utilization float64
+	AvgLatency  time.Duration
+	Throughput  float64 // Jobs per hour
+}
+
+// PoolStats summarizes one scheduling pool's end-of-run utilization, average
+// carbon intensity, queue depth, and jobs served.
+type PoolStats struct {
+	Utilization   float64 // CPU utilization percentage across the pool's nodes
+	AverageCarbon float64 // Average carbon intensity across the pool's nodes
+	QueueDepth    int     // Total queued jobs across the pool's nodes at end of run
+	JobsServed    int     // Jobs successfully scheduled onto this pool
 }
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run enhanced_simulate.go <carbon_cache.json> [duration_hours] [jobs_per_minute] [compute_job_ratio]")
+	eventsOutputFilePath := flag.String("eventsOutputFilePath", "", "optional path to write one JSON line per job state transition (arrived, queued, scheduled, started, completed, preempted)")
+	cycleStatsOutputFilePath := flag.String("cycleStatsOutputFilePath", "", "optional path to write one JSON line per scheduling tick (jobs scheduled, queue depth per node, carbon consumed, wall time)")
+	cpuProfile := flag.String("cpuProfile", "", "optional path to write a pprof CPU profile covering the whole run")
+	memProfile := flag.String("memProfile", "", "optional path to write a pprof heap profile after the run completes")
+	metricsAddr := flag.String("metrics-addr", "", "optional address (e.g. :9103) to serve live Prometheus metrics on while the simulation runs")
+	reportFormat := flag.String("report-format", "text", "report output format: text, json, or csv")
+	reportOut := flag.String("report-out", "", "path to write the json/csv report to; empty means stdout (ignored for text format)")
+	summaryOut := flag.String("summary-out", "summary.json", "path to write a machine-readable summary.json pinning the carbon-aware scheduler's reduction metrics (empty disables)")
+	rankBy := flag.String("rank-by", "carbon", "metric to rank sub-clusters/regions by in the HOTSPOTS report: carbon, latency, utilization, or throughput")
+	topN := flag.Int("top", 5, "number of worst-ranked sub-clusters/regions to show in the HOTSPOTS report")
+	flag.Usage = func() {
+		fmt.Println("Usage: go run enhanced_simulate.go [flags] <carbon_cache.json> [duration_hours] [jobs_per_minute] [compute_job_ratio]")
 		fmt.Println("Example: go run enhanced_simulate.go cache.json 1.0 5.0 0.4")
 		fmt.Println("  duration_hours: Simulation duration (default: 1.0)")
 		fmt.Println("  jobs_per_minute: Job arrival rate (default: 5.0)")
@@ -139,10 +287,47 @@ func main() {
 		fmt.Println("    - 0.0 = All I/O-bound jobs (low blocking)")
 		fmt.Println("    - 0.4 = Mix of compute and I/O (realistic)")
 		fmt.Println("    - 1.0 = All compute-intensive (matrix mult, high blocking)")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			fmt.Printf("Error creating CPU profile: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			fmt.Printf("Error starting CPU profile: %v\n", err)
+			os.Exit(1)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	tracer, err := NewTracer(*eventsOutputFilePath, *cycleStatsOutputFilePath)
+	if err != nil {
+		fmt.Printf("Error setting up trace output: %v\n", err)
 		os.Exit(1)
 	}
+	defer tracer.Close()
+
+	if *metricsAddr != "" {
+		go func() {
+			fmt.Printf("Serving Prometheus metrics on %s/metrics\n", *metricsAddr)
+			if err := metrics.Serve(*metricsAddr); err != nil {
+				fmt.Printf("Metrics server stopped: %v\n", err)
+			}
+		}()
+	}
 
-	cacheFile := os.Args[1]
+	cacheFile := args[0]
 	cache, err := readCarbonCache(cacheFile)
 	if err != nil {
 		fmt.Printf("Error reading cache: %v\n", err)
@@ -151,26 +336,38 @@ func main() {
 
 	// Initialize nodes from cache
 	nodes := initializeNodes(cache)
-	
+
 	// Add additional regions with varied carbon intensities for simulation
 	// This helps demonstrate carbon-aware scheduling advantages
 	nodes = addSimulationRegions(nodes)
-	
+
 	// Parse command-line arguments
 	durationHours := 1.0
 	jobArrivalRate := 5.0
 	computeJobRatio := 0.4 // 40% compute-intensive jobs
-	
-	if len(os.Args) >= 3 {
-		fmt.Sscanf(os.Args[2], "%f", &durationHours)
+
+	if len(args) >= 2 {
+		fmt.Sscanf(args[1], "%f", &durationHours)
 	}
-	if len(os.Args) >= 4 {
-		fmt.Sscanf(os.Args[3], "%f", &jobArrivalRate)
+	if len(args) >= 3 {
+		fmt.Sscanf(args[2], "%f", &jobArrivalRate)
 	}
-	if len(os.Args) >= 5 {
-		fmt.Sscanf(os.Args[4], "%f", &computeJobRatio)
+	if len(args) >= 4 {
+		fmt.Sscanf(args[3], "%f", &computeJobRatio)
 	}
-	
+
+	// SCHEDULER_POLICY_FILE optionally points at a JSON Policy config (see
+	// scheduler_plugins.go) that overrides every built-in scheduler's node
+	// selection with a single weighted hybrid policy, for experimenting with
+	// custom plugin combinations without touching this binary's source.
+	if policyFile := os.Getenv("SCHEDULER_POLICY_FILE"); policyFile != "" {
+		if err := LoadPolicyOverride(policyFile); err != nil {
+			fmt.Printf("Error loading scheduler policy: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Loaded scheduler policy override from %s\n", policyFile)
+	}
+
 	// Simulation configuration
 	config := SimulationConfig{
 		Duration:          time.Duration(durationHours * float64(time.Hour)),
@@ -184,6 +381,14 @@ func main() {
 		ComputeJobRatio:  computeJobRatio, // Ratio of compute-intensive jobs
 		NumNodes:         len(nodes),
 		Regions:          getRegions(nodes),
+		NumTenants:       4, // Default tenant count for the Dominant Resource Fairness scheduler
+		ProtectedFractionOfFairShare: 1.0, // Never preempt a tenant at or below its fair share
+		CrossPoolBorrowThreshold:     0.8, // Borrow into another pool once the home pool is 80% utilized
+		CrossPoolBorrowCarbonCeiling: 400.0, // Only borrow into pools averaging at or below 400 g CO2/kWh
+		MaxDeferral:      2 * time.Hour, // CarbonAwareDeferred may delay a job's start by up to 2 hours
+		ForecastTopN:     10,            // Keep the 10 lowest-scoring (node, startTime) candidates per job
+		UtilizationSampleInterval: time.Minute, // Sample node CPU/memory once per simulated minute
+		UtilizationBufferSize:     10000,       // Retain up to 10k samples per node before dropping the oldest
 	}
 
 	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
@@ -206,23 +411,74 @@ func main() {
 		{Random, "Random"},
 		{LeastLoaded, "Least-Loaded"},
 		{HighestCarbon, "Highest-Carbon (Worst Case)"},
+		{DominantResourceFairness, "Dominant-Resource-Fairness"},
+		{CarbonAwarePreemptive, "Carbon-Aware-Preemptive"},
+		{CarbonAwareDeferred, "Carbon-Aware-Deferred"},
 	}
 
 	results := make([]SimulationResults, len(schedulers))
 
 	for i, sched := range schedulers {
 		fmt.Printf("Running simulation: %s...\n", sched.desc)
-		results[i] = runSimulation(nodes, config, sched.name, sched.desc)
+		results[i] = runSimulation(nodes, config, sched.name, sched.desc, tracer)
 		fmt.Printf("✓ Completed: %s\n\n", sched.desc)
 	}
 
 	// Generate comprehensive report
-	generateReport(results, cache)
+	writer := newReportWriter(ReportFormat(*reportFormat), *reportOut, *rankBy, *topN)
+	if err := writer.WriteReport(results, cache); err != nil {
+		fmt.Printf("Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+	if err := writeSummaryJSON(results, *summaryOut); err != nil {
+		fmt.Printf("Error writing summary.json: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *memProfile != "" {
+		f, err := os.Create(*memProfile)
+		if err != nil {
+			fmt.Printf("Error creating memory profile: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			fmt.Printf("Error writing memory profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// nodePools are the capacity pools demo nodes are distributed across, cycling
+// by creation order so every pool has nodes with a spread of carbon
+// intensities (required for cross-pool borrowing to ever find a greener pool).
+var nodePools = []string{"on-demand", "spot", "gpu"}
+
+// poolForIndex assigns node index i to one of nodePools in round-robin order.
+func poolForIndex(i int) string {
+	return nodePools[i%len(nodePools)]
+}
+
+// forecastHorizonHours is how many hours of synthetic forecast each demo node
+// carries, bounding how far CarbonAwareDeferred can ever look ahead.
+const forecastHorizonHours = 6
+
+// subClusterName derives a node's sub-cluster grouping from its region and
+// pool (e.g. "us-east-1/gpu"), the finest grouping this simulator's node
+// spec can express without inventing a standalone zone concept.
+func subClusterName(region, pool string) string {
+	return fmt.Sprintf("%s/%s", region, pool)
 }
 
+// defaultPowerPerCoreWatts is the demo nodes' per-core power draw under load,
+// used for energy-weighted carbon accounting (see calculateMetrics). Roughly
+// representative of a modern cloud CPU core at full utilization.
+const defaultPowerPerCoreWatts = 15.0
+
 func initializeNodes(cache *CarbonCache) []*Node {
 	nodes := []*Node{}
-	
+
 	// Create nodes for each region
 	// REDUCED: Only 1 node per region (was 2-3) to create more contention
 	for region, data := range cache.Regions {
@@ -240,11 +496,15 @@ func initializeNodes(cache *CarbonCache) []*Node {
 				Jobs:            []*Job{},
 				RunningJobs:     []*Job{},
 				Queue:           []*Job{},
+				Pool:            poolForIndex(len(nodes)),
+				Forecast:        synthesizeForecast(data.CarbonIntensity, forecastHorizonHours),
+				PowerPerCore:    defaultPowerPerCoreWatts,
 			}
+			node.SubCluster = subClusterName(node.Region, node.Pool)
 			nodes = append(nodes, node)
 		}
 	}
-	
+
 	return nodes
 }
 
@@ -332,7 +592,11 @@ func addSimulationRegions(nodes []*Node) []*Node {
 				Jobs:            []*Job{},
 				RunningJobs:     []*Job{},
 				Queue:           []*Job{},
+				Pool:            poolForIndex(len(nodes)),
+				Forecast:        synthesizeForecast(carbonIntensity, forecastHorizonHours),
+				PowerPerCore:    defaultPowerPerCoreWatts,
 			}
+			node.SubCluster = subClusterName(node.Region, node.Pool)
 			nodes = append(nodes, node)
 		}
 	}
@@ -384,14 +648,31 @@ func createJob(jobID int, config SimulationConfig, currentTime time.Time) *Job {
 		blockingTime = duration / 2
 	}
 	
+	numTenants := config.NumTenants
+	if numTenants < 1 {
+		numTenants = 1
+	}
+
+	// Compute-intensive jobs are softly pinned to the "gpu" pool; every other
+	// job type is pool-agnostic and free to land anywhere (or to borrow into
+	// "gpu" itself, if its carbon intensity is attractive enough).
+	poolPreference := ""
+	if jobType == ComputeIntensive {
+		poolPreference = "gpu"
+	}
+
 	return &Job{
-		ID:            fmt.Sprintf("job-%d", jobID),
-		Type:          jobType,
-		CPURequest:    cpuRequest,
-		MemoryRequest: memoryRequest,
-		Duration:      duration,
-		BlockingTime:  blockingTime,
-		CreatedAt:     currentTime,
+		ID:               fmt.Sprintf("job-%d", jobID),
+		Type:             jobType,
+		CPURequest:       cpuRequest,
+		MemoryRequest:    memoryRequest,
+		Duration:         duration,
+		BlockingTime:     blockingTime,
+		CreatedAt:        currentTime,
+		Tenant:           fmt.Sprintf("tenant-%d", jobID%numTenants),
+		Priority:         rand.Intn(10), // 0-9; used by CarbonAwarePreemptive's eviction candidate selector
+		PreemptionPolicy: Preemptible,
+		PoolPreference:   poolPreference,
 	}
 }
 
@@ -407,7 +688,7 @@ func getRegions(nodes []*Node) []string {
 	return regions
 }
 
-func runSimulation(nodes []*Node, config SimulationConfig, schedulerType SchedulerType, schedulerName string) SimulationResults {
+func runSimulation(nodes []*Node, config SimulationConfig, schedulerType SchedulerType, schedulerName string, tracer *Tracer) SimulationResults {
 	// Reset nodes
 	for _, node := range nodes {
 		node.AvailableCPU = node.TotalCPU
@@ -421,12 +702,23 @@ func runSimulation(nodes []*Node, config SimulationConfig, schedulerType Schedul
 
 	startTime := time.Now()
 	endTime := startTime.Add(config.Duration)
-	
+
 	jobs := []*Job{}
 	jobID := 0
 	currentTime := startTime
 	tickInterval := 10 * time.Second // Process events every 10 seconds
-	
+
+	tenantStates := make(map[string]*TenantState)
+	clusterCap := clusterCapacity(nodes)
+	preemptedJobs := 0
+	wastedCPUHours := 0.0
+	carbonWastedOnPreemption := 0.0
+	crossPoolBorrowCount := 0
+	deferredJobs := 0
+	deferralMinutes := 0.0
+	carbonSavedByDeferral := 0.0
+	gatherer := NewUtilizationGatherer(config.UtilizationSampleInterval, config.UtilizationBufferSize)
+
 	// Simulate job arrivals and processing
 	for currentTime.Before(endTime) {
 		// Process job arrivals (Poisson process)
@@ -435,51 +727,101 @@ func runSimulation(nodes []*Node, config SimulationConfig, schedulerType Schedul
 		
 		// Process until next arrival or end time
 		for currentTime.Before(nextArrivalTime) && currentTime.Before(endTime) {
-			// Process queues and start jobs
-			processQueues(nodes, currentTime)
-			
-			// Clean up completed jobs
-			cleanupCompletedJobs(nodes, currentTime)
-			
+			runTick(nodes, currentTime, schedulerType, schedulerName, tenantStates, clusterCap, tracer, gatherer)
 			currentTime = currentTime.Add(tickInterval)
 		}
-		
+
 		if currentTime.After(endTime) {
 			break
 		}
-		
+
 		// Create new job
 		job := createJob(jobID, config, currentTime)
-		
-		// Try to schedule job
-		node := scheduleJob(job, nodes, schedulerType, currentTime)
+		tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: job.ID, Event: EventArrived})
+
+		// Try to schedule job, restricted to its pool (with cross-pool
+		// borrowing applied if its home pool is saturated and a greener pool
+		// is available)
+		candidateNodes, borrowEligible := candidateNodesForJob(job, nodes, config)
+
+		if schedulerType == CarbonAwareDeferred {
+			candidate := bestDeferredCandidate(job, candidateNodes, config.MaxDeferral, config.ForecastTopN)
+			offset := time.Duration(candidate.offsetHours) * time.Hour
+			switch {
+			case candidate.node == nil:
+				// No node can ever fit this job, now or within MaxDeferral
+				job.ScheduledAt = time.Time{}
+				jobs = append(jobs, job)
+			case offset == 0 && candidate.node.AvailableCPU >= job.CPURequest && candidate.node.AvailableMemory >= job.MemoryRequest:
+				placeJob(job, candidate.node, currentTime, tenantStates)
+				tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: job.ID, Event: EventScheduled, Node: candidate.node.Name, Region: candidate.node.Region, CarbonIntensity: candidate.node.CarbonIntensity})
+				tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: job.ID, Event: EventStarted, Node: candidate.node.Name, Region: candidate.node.Region, CarbonIntensity: candidate.node.CarbonIntensity})
+				jobs = append(jobs, job)
+			default:
+				deferredJobs++
+				deferralMinutes += offset.Minutes()
+				carbonSavedByDeferral += (candidate.node.CarbonIntensity - candidate.carbon) * job.Duration.Hours()
+
+				job.NotBefore = currentTime.Add(offset)
+				job.Node = candidate.node.Name
+				job.Region = candidate.node.Region
+				job.CarbonIntensity = candidate.carbon
+				candidate.node.Queue = append(candidate.node.Queue, job)
+				candidate.node.QueueLength = len(candidate.node.Queue)
+				tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: job.ID, Event: EventQueued, Node: candidate.node.Name, Region: candidate.node.Region, CarbonIntensity: candidate.carbon})
+				jobs = append(jobs, job)
+			}
+
+			jobID++
+			continue
+		}
+
+		node := scheduleJob(job, candidateNodes, schedulerType, currentTime)
+		if node == nil && schedulerType == CarbonAwarePreemptive {
+			// No room anywhere - see if evicting lower-priority jobs would make some
+			evictNode, victims := attemptPreemption(job, nodes, tenantStates, clusterCap, config.NumTenants, config.ProtectedFractionOfFairShare)
+			if evictNode != nil {
+				for _, victim := range victims {
+					wastedCPUHours += victim.CPURequest * currentTime.Sub(victim.StartedAt).Hours()
+					carbonWastedOnPreemption += victim.CarbonIntensity
+					preemptedJobs++
+
+					evictNode.RunningJobs = removeJob(evictNode.RunningJobs, victim)
+					evictNode.AvailableCPU += victim.CPURequest
+					evictNode.AvailableMemory += victim.MemoryRequest
+					recordTenantCompletion(tenantStates, victim)
+					tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: victim.ID, Event: EventPreempted, Node: evictNode.Name, Region: evictNode.Region, CarbonIntensity: victim.CarbonIntensity})
+
+					// Re-queue the victim so it still completes, just later
+					victim.ScheduledAt = time.Time{}
+					victim.StartedAt = time.Time{}
+					evictNode.Queue = append(evictNode.Queue, victim)
+					evictNode.QueueLength = len(evictNode.Queue)
+				}
+				node = evictNode
+			}
+		}
+
 		if node != nil {
 			// Job can start immediately
-			job.ScheduledAt = currentTime
-			job.StartedAt = currentTime
-			job.QueueTime = 0
-			job.Latency = 0
-			job.Node = node.Name
-			job.Region = node.Region
-			job.CarbonIntensity = node.CarbonIntensity
-			job.CompletedAt = currentTime.Add(job.Duration)
-			job.ExecutionTime = job.Duration
-			job.TurnaroundTime = job.Duration
-			
-			node.RunningJobs = append(node.RunningJobs, job)
-			node.AvailableCPU -= job.CPURequest
-			node.AvailableMemory -= job.MemoryRequest
-			
+			if borrowEligible && node.Pool != job.PoolPreference {
+				crossPoolBorrowCount++
+			}
+			placeJob(job, node, currentTime, tenantStates)
+			tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: job.ID, Event: EventScheduled, Node: node.Name, Region: node.Region, CarbonIntensity: node.CarbonIntensity})
+			tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: job.ID, Event: EventStarted, Node: node.Name, Region: node.Region, CarbonIntensity: node.CarbonIntensity})
 			jobs = append(jobs, job)
 		} else {
-			// No immediate resources - add to queue of best node
-			node := selectBestNodeForQueue(job, nodes, schedulerType)
+			// No immediate resources - add to queue of best node within the
+			// job's eligible (and possibly borrowed) candidate set
+			node := selectBestNodeForQueue(job, candidateNodes, schedulerType)
 			if node != nil {
 				job.Node = node.Name
 				job.Region = node.Region
 				job.CarbonIntensity = node.CarbonIntensity
 				node.Queue = append(node.Queue, job)
 				node.QueueLength = len(node.Queue)
+				tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: job.ID, Event: EventQueued, Node: node.Name, Region: node.Region, CarbonIntensity: node.CarbonIntensity})
 				jobs = append(jobs, job)
 			} else {
 				// Job failed to schedule
@@ -487,10 +829,10 @@ func runSimulation(nodes []*Node, config SimulationConfig, schedulerType Schedul
 				jobs = append(jobs, job)
 			}
 		}
-		
+
 		jobID++
 	}
-	
+
 	// Process remaining queue after simulation ends (allow jobs to complete)
 	maxProcessTime := endTime.Add(48 * time.Hour) // Allow up to 48 hours for jobs to complete
 	for currentTime.Before(maxProcessTime) {
@@ -507,22 +849,442 @@ func runSimulation(nodes []*Node, config SimulationConfig, schedulerType Schedul
 		if allQueuesEmpty && allJobsDone {
 			break
 		}
-		
-		processQueues(nodes, currentTime)
-		cleanupCompletedJobs(nodes, currentTime)
+
+		runTick(nodes, currentTime, schedulerType, schedulerName, tenantStates, clusterCap, tracer, gatherer)
 		currentTime = currentTime.Add(tickInterval)
 	}
-	
+
+	gatherer.Stop()
+	cpuUtilSummaries, memUtilSummaries, clusterUtilSeries := gatherer.NodeUtilizationSummaries()
+
 	// Calculate metrics
-	return calculateMetrics(jobs, nodes, schedulerName, config.Duration)
+	results := calculateMetrics(jobs, nodes, schedulerName, config.Duration, cpuUtilSummaries, memUtilSummaries, clusterUtilSeries)
+	results.TenantStats = snapshotTenantStats(tenantStates, clusterCap)
+	results.PreemptedJobs = preemptedJobs
+	results.WastedCPUHours = wastedCPUHours
+	results.CarbonWastedOnPreemption = carbonWastedOnPreemption
+	results.PoolStats = snapshotPoolStats(nodes, jobs)
+	results.CrossPoolBorrowCount = crossPoolBorrowCount
+	results.DeferredJobs = deferredJobs
+	results.CarbonSavedByDeferral = carbonSavedByDeferral
+	if deferredJobs > 0 {
+		results.AverageDeferralMinutes = deferralMinutes / float64(deferredJobs)
+	}
+	return results
 }
 
-// processQueues processes job queues and starts jobs when resources available
-func processQueues(nodes []*Node, currentTime time.Time) {
+// clusterCapacity sums total CPU/memory across all nodes, used to normalize a
+// tenant's resource usage into a dominant share.
+func clusterCapacity(nodes []*Node) ClusterCapacity {
+	cap := ClusterCapacity{}
 	for _, node := range nodes {
+		cap.TotalCPU += node.TotalCPU
+		cap.TotalMemory += node.TotalMemory
+	}
+	return cap
+}
+
+// recordTenantStart credits a started job's resources to its tenant's running total.
+func recordTenantStart(tenantStates map[string]*TenantState, job *Job) {
+	state, ok := tenantStates[job.Tenant]
+	if !ok {
+		state = &TenantState{}
+		tenantStates[job.Tenant] = state
+	}
+	state.CPUUsed += job.CPURequest
+	state.MemoryUsed += job.MemoryRequest
+	state.JobsServed++
+	state.CO2Attributed += job.CarbonIntensity
+}
+
+// recordTenantCompletion returns a completed job's resources to its tenant's running total.
+func recordTenantCompletion(tenantStates map[string]*TenantState, job *Job) {
+	state, ok := tenantStates[job.Tenant]
+	if !ok {
+		return
+	}
+	state.CPUUsed -= job.CPURequest
+	state.MemoryUsed -= job.MemoryRequest
+}
+
+// placeJob starts job on node at currentTime: stamps its scheduling
+// timestamps, attaches it to the node's running set, and debits the node's
+// available resources and the tenant's running totals.
+func placeJob(job *Job, node *Node, currentTime time.Time, tenantStates map[string]*TenantState) {
+	job.ScheduledAt = currentTime
+	job.StartedAt = currentTime
+	job.QueueTime = 0
+	job.Latency = 0
+	job.Node = node.Name
+	job.Region = node.Region
+	job.CarbonIntensity = node.CarbonIntensity
+	job.CompletedAt = currentTime.Add(job.Duration)
+	job.ExecutionTime = job.Duration
+	job.TurnaroundTime = job.Duration
+
+	node.RunningJobs = append(node.RunningJobs, job)
+	node.AvailableCPU -= job.CPURequest
+	node.AvailableMemory -= job.MemoryRequest
+	recordTenantStart(tenantStates, job)
+}
+
+// removeJob returns jobs with target removed, preserving order.
+func removeJob(jobs []*Job, target *Job) []*Job {
+	result := make([]*Job, 0, len(jobs))
+	for _, j := range jobs {
+		if j != target {
+			result = append(result, j)
+		}
+	}
+	return result
+}
+
+// attemptPreemption looks for a node where evicting one or more lower-
+// priority, preemptible jobs would free enough resources for job, preferring
+// victims on the highest-carbon eligible node first so eviction disturbs
+// jobs already running on green nodes the least. Jobs belonging to a tenant
+// at or below protectedFractionOfFairShare of its fair share are never
+// evicted, so preemption can't be used to starve a tenant who is already
+// behind.
+func attemptPreemption(job *Job, nodes []*Node, tenantStates map[string]*TenantState, clusterCap ClusterCapacity, numTenants int, protectedFractionOfFairShare float64) (*Node, []*Job) {
+	fairShare := 1.0
+	if numTenants > 0 {
+		fairShare = 1.0 / float64(numTenants)
+	}
+	protectedThreshold := protectedFractionOfFairShare * fairShare
+
+	candidates := make([]*Node, len(nodes))
+	copy(candidates, nodes)
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CarbonIntensity > candidates[j].CarbonIntensity
+	})
+
+	for _, node := range candidates {
+		eligible := evictionCandidates(node, job, tenantStates, clusterCap, protectedThreshold)
+		if len(eligible) == 0 {
+			continue
+		}
+
+		freedCPU, freedMemory := 0.0, 0.0
+		chosen := []*Job{}
+		for _, victim := range eligible {
+			freedCPU += victim.CPURequest
+			freedMemory += victim.MemoryRequest
+			chosen = append(chosen, victim)
+			if node.AvailableCPU+freedCPU >= job.CPURequest && node.AvailableMemory+freedMemory >= job.MemoryRequest {
+				return node, chosen
+			}
+		}
+	}
+	return nil, nil
+}
+
+// evictionCandidates returns node's preemptible running jobs with lower
+// priority than job, excluding never-preempt jobs and jobs whose tenant is
+// at or below protectedThreshold of its fair share, ordered lowest-priority
+// (cheapest to evict) first.
+func evictionCandidates(node *Node, job *Job, tenantStates map[string]*TenantState, clusterCap ClusterCapacity, protectedThreshold float64) []*Job {
+	var eligible []*Job
+	for _, running := range node.RunningJobs {
+		if running.PreemptionPolicy == NeverPreempt {
+			continue
+		}
+		if running.Priority >= job.Priority {
+			continue
+		}
+		if dominantShare(tenantStates[running.Tenant], clusterCap) <= protectedThreshold {
+			continue
+		}
+		eligible = append(eligible, running)
+	}
+	sort.Slice(eligible, func(i, j int) bool {
+		return eligible[i].Priority < eligible[j].Priority
+	})
+	return eligible
+}
+
+// snapshotTenantStats converts the live tenantStates map into the per-tenant
+// report fields attached to SimulationResults.
+func snapshotTenantStats(tenantStates map[string]*TenantState, capacity ClusterCapacity) map[string]TenantStats {
+	stats := make(map[string]TenantStats, len(tenantStates))
+	for tenant, state := range tenantStates {
+		stats[tenant] = TenantStats{
+			DominantShare: dominantShare(state, capacity),
+			JobsServed:    state.JobsServed,
+			CO2Attributed: state.CO2Attributed,
+		}
+	}
+	return stats
+}
+
+// jobPoolConstraint returns the pools a job is restricted to and whether that
+// restriction is hard. AllowedPools is a hard restriction (no borrowing);
+// PoolPreference with no AllowedPools is a soft pin eligible for cross-pool
+// borrowing. A nil pools list means the job is pool-agnostic.
+func jobPoolConstraint(job *Job) (pools []string, hard bool) {
+	if len(job.AllowedPools) > 0 {
+		return job.AllowedPools, true
+	}
+	if job.PoolPreference != "" {
+		return []string{job.PoolPreference}, false
+	}
+	return nil, false
+}
+
+// distinctPools returns the distinct Pool values present across nodes.
+func distinctPools(nodes []*Node) []string {
+	seen := make(map[string]bool)
+	var pools []string
+	for _, n := range nodes {
+		if !seen[n.Pool] {
+			seen[n.Pool] = true
+			pools = append(pools, n.Pool)
+		}
+	}
+	return pools
+}
+
+// nodesInPools returns the subset of nodes whose Pool is in pools, or all
+// nodes if pools is nil (no restriction).
+func nodesInPools(nodes []*Node, pools []string) []*Node {
+	if pools == nil {
+		return nodes
+	}
+	allowed := make(map[string]bool, len(pools))
+	for _, p := range pools {
+		allowed[p] = true
+	}
+	filtered := make([]*Node, 0, len(nodes))
+	for _, n := range nodes {
+		if allowed[n.Pool] {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
+
+// poolUtilization returns the fraction (0-1) of total CPU in use across nodes.
+func poolUtilization(nodes []*Node) float64 {
+	var totalCPU, availableCPU float64
+	for _, n := range nodes {
+		totalCPU += n.TotalCPU
+		availableCPU += n.AvailableCPU
+	}
+	if totalCPU == 0 {
+		return 0
+	}
+	return 1.0 - availableCPU/totalCPU
+}
+
+// poolAverageCarbon returns the average carbon intensity across nodes.
+func poolAverageCarbon(nodes []*Node) float64 {
+	if len(nodes) == 0 {
+		return 0
+	}
+	var total float64
+	for _, n := range nodes {
+		total += n.CarbonIntensity
+	}
+	return total / float64(len(nodes))
+}
+
+// candidateNodesForJob resolves which nodes a job may be scheduled on this
+// attempt. A hard AllowedPools restriction or a pool-agnostic job is returned
+// as-is. A soft PoolPreference is expanded to include every other pool whose
+// average carbon intensity is at or below config.CrossPoolBorrowCarbonCeiling,
+// but only once the home pool's utilization exceeds
+// config.CrossPoolBorrowThreshold — mirroring how real batch schedulers only
+// burst a saturated pool into another when there's a sustainability upside.
+func candidateNodesForJob(job *Job, nodes []*Node, config SimulationConfig) (candidates []*Node, borrowEligible bool) {
+	pools, hard := jobPoolConstraint(job)
+	home := nodesInPools(nodes, pools)
+	if hard || pools == nil {
+		return home, false
+	}
+
+	if poolUtilization(home) <= config.CrossPoolBorrowThreshold {
+		return home, false
+	}
+
+	candidates = append([]*Node{}, home...)
+	for _, pool := range distinctPools(nodes) {
+		if pool == pools[0] {
+			continue
+		}
+		poolNodes := nodesInPools(nodes, []string{pool})
+		if poolAverageCarbon(poolNodes) <= config.CrossPoolBorrowCarbonCeiling {
+			candidates = append(candidates, poolNodes...)
+		}
+	}
+	return candidates, true
+}
+
+// snapshotPoolStats computes per-pool utilization, average carbon intensity,
+// queue depth, and jobs served at the end of a run.
+func snapshotPoolStats(nodes []*Node, jobs []*Job) map[string]PoolStats {
+	nodePool := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		nodePool[n.Name] = n.Pool
+	}
+
+	stats := make(map[string]PoolStats)
+	for _, pool := range distinctPools(nodes) {
+		poolNodes := nodesInPools(nodes, []string{pool})
+		queueDepth := 0
+		for _, n := range poolNodes {
+			queueDepth += len(n.Queue)
+		}
+		stats[pool] = PoolStats{
+			Utilization:   poolUtilization(poolNodes) * 100.0,
+			AverageCarbon: poolAverageCarbon(poolNodes),
+			QueueDepth:    queueDepth,
+		}
+	}
+
+	for _, job := range jobs {
+		if job.ScheduledAt.IsZero() {
+			continue
+		}
+		pool, ok := nodePool[job.Node]
+		if !ok {
+			continue
+		}
+		entry := stats[pool]
+		entry.JobsServed++
+		stats[pool] = entry
+	}
+	return stats
+}
+
+// synthesizeForecast builds a deterministic hourly carbon-intensity forecast
+// around a node's current intensity, following a diurnal pattern (renewable
+// generation peaks midday, so intensity dips then rises) since the demo cache
+// files carry only a single current-intensity scalar, not real forecast data.
+func synthesizeForecast(carbonIntensity float64, hours int) []float64 {
+	forecast := make([]float64, hours+1)
+	for h := 0; h <= hours; h++ {
+		swing := 0.3 * math.Sin(float64(h)/24*2*math.Pi)
+		forecast[h] = carbonIntensity * (1.0 - swing)
+	}
+	return forecast
+}
+
+// forecastAt returns node's forecasted carbon intensity offsetHours into the
+// future, falling back to its current (flat) CarbonIntensity if no forecast
+// was loaded, or clamping to the last known point beyond the forecast horizon.
+func forecastAt(node *Node, offsetHours int) float64 {
+	if len(node.Forecast) == 0 {
+		return node.CarbonIntensity
+	}
+	if offsetHours >= len(node.Forecast) {
+		return node.Forecast[len(node.Forecast)-1]
+	}
+	return node.Forecast[offsetHours]
+}
+
+// deferralPenalty models the cost of making a job wait, so CarbonAwareDeferred
+// won't trade a long delay for a negligible carbon improvement.
+const deferralPenaltyPerMinuteGCO2 = 0.5
+
+func deferralPenalty(offset time.Duration) float64 {
+	return offset.Minutes() * deferralPenaltyPerMinuteGCO2
+}
+
+// forecastCandidate is one (node, deferral offset) option considered by
+// CarbonAwareDeferred, scored by projected carbon cost plus the penalty for
+// making the job wait longer.
+type forecastCandidate struct {
+	node        *Node
+	offsetHours int
+	carbon      float64
+	score       float64
+}
+
+// candidateMaxHeap is a bounded max-heap over forecastCandidate.score: it
+// keeps only the N lowest-scoring candidates seen so far while scanning every
+// node's forecasted timeslots, so bestDeferredCandidate never has to sort or
+// retain the full (node x timeslot) candidate set.
+type candidateMaxHeap []forecastCandidate
+
+func (h candidateMaxHeap) Len() int          { return len(h) }
+func (h candidateMaxHeap) Less(i, j int) bool { return h[i].score > h[j].score }
+func (h candidateMaxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *candidateMaxHeap) Push(x interface{}) {
+	*h = append(*h, x.(forecastCandidate))
+}
+func (h *candidateMaxHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// bestDeferredCandidate scans every candidate node's forecasted timeslots up
+// to maxDeferral (hourly granularity), keeping only the topN lowest-scoring
+// (node, offset) candidates in a bounded heap, and returns the single best of
+// those. Score = carbonAtOffset * job duration (hours) + deferralPenalty(offset).
+// Returns a zero-value candidate with a nil node if no node could ever fit
+// the job's resource request.
+func bestDeferredCandidate(job *Job, nodes []*Node, maxDeferral time.Duration, topN int) forecastCandidate {
+	maxHours := int(maxDeferral.Hours())
+	h := &candidateMaxHeap{}
+	heap.Init(h)
+
+	for _, node := range nodes {
+		if node.TotalCPU < job.CPURequest || node.TotalMemory < job.MemoryRequest {
+			continue // this node could never fit the job, at any offset
+		}
+		for offset := 0; offset <= maxHours; offset++ {
+			carbon := forecastAt(node, offset)
+			candidate := forecastCandidate{
+				node:        node,
+				offsetHours: offset,
+				carbon:      carbon,
+				score:       carbon*job.Duration.Hours() + deferralPenalty(time.Duration(offset)*time.Hour),
+			}
+
+			if h.Len() < topN {
+				heap.Push(h, candidate)
+			} else if candidate.score < (*h)[0].score {
+				heap.Pop(h)
+				heap.Push(h, candidate)
+			}
+		}
+	}
+
+	best := forecastCandidate{score: math.MaxFloat64}
+	for _, c := range *h {
+		if c.score < best.score {
+			best = c
+		}
+	}
+	return best
+}
+
+// processQueues processes job queues and starts jobs when resources available.
+// Under DominantResourceFairness, each node's queue is first sorted so the
+// tenant with the smallest current dominant share is tried first; sustainability
+// still breaks ties because jobs are otherwise queued onto low-carbon nodes
+// by selectBestNodeForQueue.
+func processQueues(nodes []*Node, currentTime time.Time, schedulerType SchedulerType, schedulerName string, tenantStates map[string]*TenantState, capacity ClusterCapacity, tracer *Tracer) int {
+	jobsScheduled := 0
+	for _, node := range nodes {
+		queue := node.Queue
+		if schedulerType == DominantResourceFairness {
+			sort.Slice(queue, func(i, j int) bool {
+				return dominantShare(tenantStates[queue[i].Tenant], capacity) < dominantShare(tenantStates[queue[j].Tenant], capacity)
+			})
+		}
+
 		// Try to start queued jobs
 		newQueue := []*Job{}
-		for _, queuedJob := range node.Queue {
+		for _, queuedJob := range queue {
+			if !queuedJob.NotBefore.IsZero() && currentTime.Before(queuedJob.NotBefore) {
+				// CarbonAwareDeferred: still waiting for its chosen start window
+				newQueue = append(newQueue, queuedJob)
+				continue
+			}
 			if node.AvailableCPU >= queuedJob.CPURequest && node.AvailableMemory >= queuedJob.MemoryRequest {
 				// Can start this job
 				queuedJob.ScheduledAt = currentTime
@@ -532,10 +1294,14 @@ func processQueues(nodes []*Node, currentTime time.Time) {
 				queuedJob.CompletedAt = currentTime.Add(queuedJob.Duration)
 				queuedJob.ExecutionTime = queuedJob.Duration
 				queuedJob.TurnaroundTime = queuedJob.CompletedAt.Sub(queuedJob.CreatedAt)
-				
+
 				node.RunningJobs = append(node.RunningJobs, queuedJob)
 				node.AvailableCPU -= queuedJob.CPURequest
 				node.AvailableMemory -= queuedJob.MemoryRequest
+				recordTenantStart(tenantStates, queuedJob)
+				jobsScheduled++
+				tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: queuedJob.ID, Event: EventScheduled, Node: node.Name, Region: node.Region, CarbonIntensity: node.CarbonIntensity})
+				tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: queuedJob.ID, Event: EventStarted, Node: node.Name, Region: node.Region, CarbonIntensity: node.CarbonIntensity})
 			} else {
 				// Still waiting
 				newQueue = append(newQueue, queuedJob)
@@ -544,10 +1310,13 @@ func processQueues(nodes []*Node, currentTime time.Time) {
 		node.Queue = newQueue
 		node.QueueLength = len(newQueue)
 	}
+	return jobsScheduled
 }
 
-// cleanupCompletedJobs removes completed jobs and frees resources
-func cleanupCompletedJobs(nodes []*Node, currentTime time.Time) {
+// cleanupCompletedJobs removes completed jobs and frees resources, returning
+// the total carbon intensity attributed to jobs that completed this tick.
+func cleanupCompletedJobs(nodes []*Node, currentTime time.Time, schedulerName string, tenantStates map[string]*TenantState, tracer *Tracer) float64 {
+	carbonConsumed := 0.0
 	for _, node := range nodes {
 		newRunningJobs := []*Job{}
 		for _, job := range node.RunningJobs {
@@ -558,71 +1327,55 @@ func cleanupCompletedJobs(nodes []*Node, currentTime time.Time) {
 				// Job completed, free resources
 				node.AvailableCPU += job.CPURequest
 				node.AvailableMemory += job.MemoryRequest
+				recordTenantCompletion(tenantStates, job)
+				carbonConsumed += job.CarbonIntensity
+				tracer.RecordEvent(JobEvent{Timestamp: currentTime, SchedulerType: schedulerName, JobID: job.ID, Event: EventCompleted, Node: node.Name, Region: node.Region, CarbonIntensity: job.CarbonIntensity})
 			}
 		}
 		node.RunningJobs = newRunningJobs
-		
+
 		// Update utilization
 		usedCPU := node.TotalCPU - node.AvailableCPU
 		node.Utilization = (usedCPU / node.TotalCPU) * 100.0
 	}
+	return carbonConsumed
 }
 
-func scheduleJob(job *Job, nodes []*Node, schedulerType SchedulerType, currentTime time.Time) *Node {
-	// Filter nodes with available resources
-	availableNodes := []*Node{}
+// runTick advances one scheduling tick: starts eligible queued jobs, frees
+// resources from completed jobs, and records a CycleStats line summarizing
+// the tick's work and wall-clock cost.
+func runTick(nodes []*Node, currentTime time.Time, schedulerType SchedulerType, schedulerName string, tenantStates map[string]*TenantState, capacity ClusterCapacity, tracer *Tracer, gatherer *UtilizationGatherer) {
+	tickStart := time.Now()
+
+	jobsScheduled := processQueues(nodes, currentTime, schedulerType, schedulerName, tenantStates, capacity, tracer)
+	carbonConsumed := cleanupCompletedJobs(nodes, currentTime, schedulerName, tenantStates, tracer)
+	gatherer.Sample(currentTime, nodes)
+
+	queueDepths := make(map[string]int, len(nodes))
 	for _, node := range nodes {
-		if node.AvailableCPU >= job.CPURequest && node.AvailableMemory >= job.MemoryRequest {
-			availableNodes = append(availableNodes, node)
-		}
-	}
-	
-	if len(availableNodes) == 0 {
-		return nil // No immediate resources
+		queueDepths[node.Name] = node.QueueLength
 	}
-	
-	return selectNode(availableNodes, schedulerType)
+
+	tracer.RecordCycle(CycleStats{
+		Timestamp:        currentTime,
+		SchedulerType:    schedulerName,
+		JobsScheduled:    jobsScheduled,
+		QueueDepths:      queueDepths,
+		CarbonConsumed:   carbonConsumed,
+		CycleWallTimeSec: time.Since(tickStart).Seconds(),
+	})
 }
 
-// selectBestNodeForQueue selects the best node for queuing when no immediate resources
+func scheduleJob(job *Job, nodes []*Node, schedulerType SchedulerType, currentTime time.Time) *Node {
+	return selectNode(nodes, schedulerType, job)
+}
+
+// selectBestNodeForQueue selects the best node for queuing when no immediate
+// resources are available, scoring every node (regardless of fit) by its
+// estimated wait time blended with the scheduler's sustainability/load axis.
 func selectBestNodeForQueue(job *Job, nodes []*Node, schedulerType SchedulerType) *Node {
-	// Estimate wait time for each node and select best
-	bestNode := (*Node)(nil)
-	bestScore := math.MaxFloat64
-	
-	for _, node := range nodes {
-		// Estimate wait time based on current load and queue length
-		utilization := (node.TotalCPU - node.AvailableCPU) / node.TotalCPU
-		estimatedWait := estimateWaitTime(node, job, utilization)
-		
-		var score float64
-		switch schedulerType {
-		case CarbonAware:
-			// Score = wait time + carbon penalty
-			// Heavily weight carbon intensity to strongly prefer low-carbon nodes
-			// Even if they have longer queues, prefer low-carbon
-			score = float64(estimatedWait)*0.1 + node.CarbonIntensity*10000 // Carbon dominates
-		case LeastLoaded:
-			// Score = wait time + utilization penalty
-			score = float64(estimatedWait) + utilization*10000
-		case RoundRobin:
-			// Score = wait time + queue length
-			score = float64(estimatedWait) + float64(len(node.Queue))*1000
-		case HighestCarbon:
-			// Score = wait time - carbon bonus (prefer high carbon)
-			// Make worst-case scheduler truly prefer highest carbon
-			score = float64(estimatedWait)*0.1 - node.CarbonIntensity*10000 // Carbon dominates (negative = prefer high)
-		default:
-			score = float64(estimatedWait)
-		}
-		
-		if score < bestScore {
-			bestScore = score
-			bestNode = node
-		}
-	}
-	
-	return bestNode
+	policy := queuePolicyForSchedulerType(schedulerType)
+	return policy.SelectNode(nodes, job, nil)
 }
 
 func estimateWaitTime(node *Node, job *Job, utilization float64) time.Duration {
@@ -646,53 +1399,19 @@ func estimateWaitTime(node *Node, job *Job, utilization float64) time.Duration {
 	return baseWait + queueWait
 }
 
-func selectNode(availableNodes []*Node, schedulerType SchedulerType) *Node {
-	if len(availableNodes) == 0 {
+// selectNode picks a node for job using the Policy registered for
+// schedulerType (either the scheduler-specific built-in default, or a
+// --policy-config override applying to every scheduler run). job may be nil
+// when no resource/taint/affinity filtering is required by the caller.
+func selectNode(nodes []*Node, schedulerType SchedulerType, job *Job) *Node {
+	if len(nodes) == 0 {
 		return nil
 	}
-	
-	switch schedulerType {
-	case CarbonAware:
-		// Select node with lowest carbon intensity
-		sort.Slice(availableNodes, func(i, j int) bool {
-			return availableNodes[i].CarbonIntensity < availableNodes[j].CarbonIntensity
-		})
-		return availableNodes[0]
-		
-	case RoundRobin:
-		// Round-robin: select based on job count
-		sort.Slice(availableNodes, func(i, j int) bool {
-			return len(availableNodes[i].Jobs) < len(availableNodes[j].Jobs)
-		})
-		return availableNodes[0]
-		
-	case Random:
-		return availableNodes[rand.Intn(len(availableNodes))]
-		
-	case LeastLoaded:
-		// Select node with most available resources
-		sort.Slice(availableNodes, func(i, j int) bool {
-			utilI := (availableNodes[i].TotalCPU - availableNodes[i].AvailableCPU) / availableNodes[i].TotalCPU
-			utilJ := (availableNodes[j].TotalCPU - availableNodes[j].AvailableCPU) / availableNodes[j].TotalCPU
-			return utilI < utilJ
-		})
-		return availableNodes[0]
-		
-		case HighestCarbon:
-			// Worst case: select highest carbon
-			// Always prefer highest carbon, even if it means longer wait
-			sort.Slice(availableNodes, func(i, j int) bool {
-				return availableNodes[i].CarbonIntensity > availableNodes[j].CarbonIntensity
-			})
-			return availableNodes[0]
-		
-	default:
-		return availableNodes[0]
-	}
+	policy := policyForSchedulerType(schedulerType)
+	return policy.SelectNode(nodes, job, nil)
 }
 
-
-func calculateMetrics(jobs []*Job, nodes []*Node, schedulerName string, duration time.Duration) SimulationResults {
+func calculateMetrics(jobs []*Job, nodes []*Node, schedulerName string, duration time.Duration, cpuUtilSummaries, memUtilSummaries map[string]UtilizationSummary, clusterUtilSeries UtilizationSummary) SimulationResults {
 	results := SimulationResults{
 		SchedulerType:      schedulerName,
 		TotalJobs:          len(jobs),
@@ -700,106 +1419,219 @@ func calculateMetrics(jobs []*Job, nodes []*Node, schedulerName string, duration
 		RegionDistribution: make(map[string]int),
 		CarbonByRegion:     make(map[string]float64),
 		JobsByRegion:       make(map[string]int),
+		CO2GramsByRegion:   make(map[string]float64),
+		CoreHoursByRegion:  make(map[string]float64),
 	}
-	
+
 	if len(jobs) == 0 {
 		return results
 	}
-	
-	// Calculate carbon metrics
+
+	// Calculate carbon metrics. Latency/turnaround percentiles are derived from
+	// Prometheus histograms rather than accumulated slices, so long runs don't
+	// balloon memory holding every job's raw latency/turnaround.
 	totalCarbon := 0.0
-	latencies := []float64{}
-	turnarounds := []float64{}
-	
+	latencySum := 0.0
+	turnaroundSum := 0.0
+
+	// nodePowerPerCore/nodeSubCluster resolve a completed job's node to its
+	// PowerPerCore and SubCluster, for the energy-weighted CO2 accounting and
+	// the per-sub-cluster HOTSPOTS stats below.
+	nodePowerPerCore := make(map[string]float64, len(nodes))
+	nodeSubCluster := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		nodePowerPerCore[n.Name] = n.PowerPerCore
+		nodeSubCluster[n.Name] = n.SubCluster
+	}
+
+	latencyHist, _ := metrics.LatencySeconds.GetMetricWithLabelValues(schedulerName)
+	turnaroundHist, _ := metrics.TurnaroundSeconds.GetMetricWithLabelValues(schedulerName)
+
+	subClusterJobs := make(map[string]int)
+	subClusterCarbon := make(map[string]float64)
+	regionLatencySum := make(map[string]float64)
+
 	for _, job := range jobs {
 		if job.ScheduledAt.IsZero() {
 			results.FailedJobs++
 			continue
 		}
-		
+
 		results.CompletedJobs++
 		totalCarbon += job.CarbonIntensity
-		latencies = append(latencies, float64(job.Latency))
-		turnarounds = append(turnarounds, float64(job.TurnaroundTime))
-		
+		latencySum += float64(job.Latency)
+		turnaroundSum += float64(job.TurnaroundTime)
+
 		results.RegionDistribution[job.Region]++
 		results.JobsByRegion[job.Region]++
 		results.CarbonByRegion[job.Region] += job.CarbonIntensity
+		regionLatencySum[job.Region] += float64(job.Latency)
+
+		subCluster := nodeSubCluster[job.Node]
+		subClusterJobs[subCluster]++
+		subClusterCarbon[subCluster] += job.CarbonIntensity
+		if subClusterHist, err := metrics.SubClusterLatencySeconds.GetMetricWithLabelValues(schedulerName, subCluster); err == nil {
+			subClusterHist.Observe(job.Latency.Seconds())
+		}
+
+		// Energy-weighted CO2 accounting: a job's footprint scales with how
+		// many cores it holds and for how long, not just which region it
+		// landed in, so a scheduler can't look artificially clean by packing
+		// many short jobs into a dirty region.
+		runtimeHours := job.ExecutionTime.Hours()
+		coreHours := job.CPURequest * runtimeHours
+		powerPerCore := nodePowerPerCore[job.Node]
+		if powerPerCore == 0 {
+			powerPerCore = defaultPowerPerCoreWatts
+		}
+		co2Grams := job.CarbonIntensity * coreHours * (powerPerCore / 1000.0)
+
+		results.TotalCO2Grams += co2Grams
+		results.CoreHours += coreHours
+		results.NodeHours += runtimeHours
+		results.CO2GramsByRegion[job.Region] += co2Grams
+		results.CoreHoursByRegion[job.Region] += coreHours
+
+		metrics.JobsCompletedTotal.WithLabelValues(schedulerName, job.Region).Inc()
+		metrics.JobCarbonIntensity.WithLabelValues(schedulerName, job.Region).Observe(job.CarbonIntensity)
+		metrics.JobCarbonIntensityOverall.WithLabelValues(schedulerName).Observe(job.CarbonIntensity)
+		if latencyHist != nil {
+			latencyHist.Observe(job.Latency.Seconds())
+		}
+		if turnaroundHist != nil {
+			turnaroundHist.Observe(job.TurnaroundTime.Seconds())
+		}
 	}
-	
+
 	results.AverageCarbon = totalCarbon / float64(results.CompletedJobs)
 	results.TotalCarbon = totalCarbon
-	
-	// Calculate latency metrics
-	if len(latencies) > 0 {
-		sort.Float64s(latencies)
-		// Average latency (mean)
-		sum := 0.0
-		for _, l := range latencies {
-			sum += l
-		}
-		results.AverageLatency = time.Duration(sum / float64(len(latencies)))
-		
-		// P95 latency
-		if len(latencies) >= 20 {
-			p95Index := int(float64(len(latencies)) * 0.95)
-			results.P95Latency = time.Duration(latencies[p95Index])
-		} else {
-			results.P95Latency = time.Duration(latencies[len(latencies)-1])
+	if results.CoreHours > 0 {
+		results.AvgCarbonPerCoreHour = results.TotalCO2Grams / results.CoreHours
+	}
+
+	if results.CompletedJobs > 0 {
+		results.AverageLatency = time.Duration(latencySum / float64(results.CompletedJobs))
+		results.AverageTurnaround = time.Duration(turnaroundSum / float64(results.CompletedJobs))
+		if latencyHist != nil {
+			results.P95Latency = time.Duration(metrics.HistogramPercentile(latencyHist, 0.95) * float64(time.Second))
+		}
+		if turnaroundHist != nil {
+			results.P95Turnaround = time.Duration(metrics.HistogramPercentile(turnaroundHist, 0.95) * float64(time.Second))
+		}
+		if overallCarbonHist, err := metrics.JobCarbonIntensityOverall.GetMetricWithLabelValues(schedulerName); err == nil {
+			results.P95Carbon = metrics.HistogramPercentile(overallCarbonHist, 0.95)
 		}
 	}
-	
-	// Calculate turnaround metrics
-	if len(turnarounds) > 0 {
-		sort.Float64s(turnarounds)
-		// Average turnaround (mean)
-		sum := 0.0
-		for _, t := range turnarounds {
-			sum += t
-		}
-		results.AverageTurnaround = time.Duration(sum / float64(len(turnarounds)))
-		
-		// P95 turnaround
-		if len(turnarounds) >= 20 {
-			p95Index := int(float64(len(turnarounds)) * 0.95)
-			results.P95Turnaround = time.Duration(turnarounds[p95Index])
-		} else {
-			results.P95Turnaround = time.Duration(turnarounds[len(turnarounds)-1])
+
+	results.CarbonByRegionP95 = make(map[string]float64, len(results.JobsByRegion))
+	for region := range results.JobsByRegion {
+		if regionHist, err := metrics.JobCarbonIntensity.GetMetricWithLabelValues(schedulerName, region); err == nil {
+			results.CarbonByRegionP95[region] = metrics.HistogramPercentile(regionHist, 0.95)
 		}
 	}
-	
-	// Calculate utilization (average over simulation time)
-	// Track peak utilization and average utilization
-	totalUtilization := 0.0
-	totalPeakUtilization := 0.0
-	for _, node := range nodes {
-		// Current utilization (at end of simulation)
-		currentUtilization := (node.TotalCPU - node.AvailableCPU) / node.TotalCPU * 100.0
-		
-		// Estimate average utilization based on completed jobs
-		// This is simplified - ideally we'd track utilization over time
-		totalJobCPU := 0.0
-		for _, job := range jobs {
-			if job.Node == node.Name && !job.ScheduledAt.IsZero() {
-				totalJobCPU += job.CPURequest
-			}
+
+	// Group nodes by sub-cluster and region so the HOTSPOTS report can rank
+	// both groupings by the caller's chosen --rank-by metric.
+	subClusterNodes := make(map[string][]string)
+	regionNodes := make(map[string][]string)
+	for _, n := range nodes {
+		subClusterNodes[n.SubCluster] = append(subClusterNodes[n.SubCluster], n.Name)
+		regionNodes[n.Region] = append(regionNodes[n.Region], n.Name)
+	}
+
+	results.SubClusterStats = make(map[string]SubClusterStats, len(subClusterJobs))
+	for sc, jobs := range subClusterJobs {
+		var utilSum float64
+		members := subClusterNodes[sc]
+		for _, name := range members {
+			utilSum += cpuUtilSummaries[name].Mean
+		}
+		utilization := 0.0
+		if len(members) > 0 {
+			utilization = utilSum / float64(len(members))
+		}
+		var p95Latency time.Duration
+		if subClusterHist, err := metrics.SubClusterLatencySeconds.GetMetricWithLabelValues(schedulerName, sc); err == nil {
+			p95Latency = time.Duration(metrics.HistogramPercentile(subClusterHist, 0.95) * float64(time.Second))
+		}
+		results.SubClusterStats[sc] = SubClusterStats{
+			Jobs:        jobs,
+			AvgCarbon:   subClusterCarbon[sc] / float64(jobs),
+			Utilization: utilization,
+			P95Latency:  p95Latency,
+			Throughput:  float64(jobs) / duration.Hours(),
 		}
-		// Average utilization = total CPU used / (node capacity * simulation duration factor)
-		avgUtilization := math.Min(100.0, (totalJobCPU / node.TotalCPU) * 100.0)
-		
-		results.NodeUtilization[node.Name] = avgUtilization
-		totalUtilization += avgUtilization
-		totalPeakUtilization += currentUtilization
 	}
-	results.AverageUtilization = totalUtilization / float64(len(nodes))
-	
+
+	results.RegionStats = make(map[string]RegionStats, len(results.JobsByRegion))
+	for region, jobs := range results.JobsByRegion {
+		var utilSum float64
+		members := regionNodes[region]
+		for _, name := range members {
+			utilSum += cpuUtilSummaries[name].Mean
+		}
+		utilization := 0.0
+		if len(members) > 0 {
+			utilization = utilSum / float64(len(members))
+		}
+		results.RegionStats[region] = RegionStats{
+			Jobs:        jobs,
+			AvgCarbon:   results.CarbonByRegion[region] / float64(jobs),
+			Utilization: utilization,
+			AvgLatency:  time.Duration(regionLatencySum[region] / float64(jobs)),
+			Throughput:  float64(jobs) / duration.Hours(),
+		}
+	}
+
+
+	// Node/cluster utilization now comes from the UtilizationGatherer's
+	// time-sampled series rather than an end-of-run snapshot, giving a true
+	// time-weighted average instead of a point-in-time estimate.
+	results.NodeUtilizationP95 = make(map[string]float64, len(cpuUtilSummaries))
+	results.NodeMemP95 = make(map[string]float64, len(memUtilSummaries))
+	for _, node := range nodes {
+		results.NodeUtilization[node.Name] = cpuUtilSummaries[node.Name].Mean
+		results.NodeUtilizationP95[node.Name] = cpuUtilSummaries[node.Name].P95
+		results.NodeMemP95[node.Name] = memUtilSummaries[node.Name].P95
+		metrics.NodeCPUUtilization.WithLabelValues(node.Name).Set(cpuUtilSummaries[node.Name].Mean)
+	}
+	results.AverageUtilization = clusterUtilSeries.Mean
+	results.ClusterUtilSeries = clusterUtilSeries
+
 	// Calculate throughput
 	results.Throughput = float64(results.CompletedJobs) / duration.Hours()
 	
 	return results
 }
 
-func generateReport(results []SimulationResults, cache *CarbonCache) {
+// hotspotEntry is one ranked row in the HOTSPOTS table: either a sub-cluster
+// or a region, normalized to the fields --rank-by can sort by.
+type hotspotEntry struct {
+	Label       string
+	Jobs        int
+	AvgCarbon   float64
+	Utilization float64
+	Latency     time.Duration
+	Throughput  float64
+}
+
+// rankValue extracts the metric named by rankBy from a hotspotEntry, falling
+// back to AvgCarbon for an unrecognized rankBy so a typo'd flag still produces
+// a sensibly-ordered table rather than an unsorted one.
+func rankValue(rankBy string, e hotspotEntry) float64 {
+	switch rankBy {
+	case "latency":
+		return e.Latency.Seconds()
+	case "utilization":
+		return e.Utilization
+	case "throughput":
+		return e.Throughput
+	default:
+		return e.AvgCarbon
+	}
+}
+
+func generateReport(results []SimulationResults, cache *CarbonCache, rankBy string, topN int) {
 	fmt.Println("╔══════════════════════════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║                    COMPREHENSIVE SIMULATION RESULTS                                 ║")
 	fmt.Println("╠══════════════════════════════════════════════════════════════════════════════════════╣")
@@ -823,6 +1655,15 @@ func generateReport(results []SimulationResults, cache *CarbonCache) {
 	if worstCase.AverageLatency > 0 {
 		carbonAware.LatencyReduction = ((float64(worstCase.AverageLatency) - float64(carbonAware.AverageLatency)) / float64(worstCase.AverageLatency)) * 100.0
 	}
+
+	// Publish each scheduler's carbon reduction versus worst-case as a final
+	// end-of-run gauge, for dashboards comparing runs over time.
+	if worstCase.AverageCarbon > 0 {
+		for _, r := range results {
+			reduction := ((worstCase.AverageCarbon - r.AverageCarbon) / worstCase.AverageCarbon) * 100.0
+			metrics.CarbonReductionRatio.WithLabelValues(r.SchedulerType).Set(reduction)
+		}
+	}
 	
 	// Print comparison table
 	fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
@@ -851,20 +1692,57 @@ func generateReport(results []SimulationResults, cache *CarbonCache) {
 	fmt.Printf("│ Worst-Case Average:          %.2f g CO2/kWh                                           │\n", worstCase.AverageCarbon)
 	fmt.Printf("│ Carbon Reduction:            %.2f%%                                                    │\n", carbonAware.CarbonReduction)
 	fmt.Printf("│ Total Carbon Saved:          %.2f g CO2/kWh                                           │\n", worstCase.TotalCarbon-carbonAware.TotalCarbon)
+	fmt.Printf("│ Total CO2 Emitted (energy-weighted): %.2f g                                           │\n", carbonAware.TotalCO2Grams)
+	fmt.Printf("│ Avg Carbon per Core-Hour:    %.2f g CO2/core-hr                                        │\n", carbonAware.AvgCarbonPerCoreHour)
+	fmt.Printf("│ Core-Hours / Node-Hours:     %.1f / %.1f                                               │\n", carbonAware.CoreHours, carbonAware.NodeHours)
 	fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
 	fmt.Println()
-	
+
 	// Region distribution
 	fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
-	fmt.Println("│                    CARBON-AWARE REGION DISTRIBUTION                                  │")
+	fmt.Println("│          CARBON-AWARE REGION DISTRIBUTION (raw vs. energy-weighted avg)             │")
 	fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
 	for region, count := range carbonAware.RegionDistribution {
 		avgCarbon := carbonAware.CarbonByRegion[region] / float64(count)
-		fmt.Printf("│ %-20s: %4d jobs (%.2f g CO2/kWh avg)                                    │\n", region, count, avgCarbon)
+		weightedAvg := 0.0
+		if coreHours := carbonAware.CoreHoursByRegion[region]; coreHours > 0 {
+			weightedAvg = carbonAware.CO2GramsByRegion[region] / coreHours
+		}
+		fmt.Printf("│ %-20s: %4d jobs (%.2f g CO2/kWh raw avg, %.2f g CO2/core-hr weighted)       │\n", region, count, avgCarbon, weightedAvg)
 	}
 	fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
 	fmt.Println()
-	
+
+	// HOTSPOTS: sub-clusters and regions ranked by the caller's chosen metric,
+	// so "which zone contributed most of the carbon savings?" is a sorted
+	// table instead of eyeballing the unsorted region distribution above.
+	if len(carbonAware.SubClusterStats) > 0 || len(carbonAware.RegionStats) > 0 {
+		var entries []hotspotEntry
+		for name, s := range carbonAware.SubClusterStats {
+			entries = append(entries, hotspotEntry{Label: "subcluster:" + name, Jobs: s.Jobs, AvgCarbon: s.AvgCarbon, Utilization: s.Utilization, Latency: s.P95Latency, Throughput: s.Throughput})
+		}
+		for name, s := range carbonAware.RegionStats {
+			entries = append(entries, hotspotEntry{Label: "region:" + name, Jobs: s.Jobs, AvgCarbon: s.AvgCarbon, Utilization: s.Utilization, Latency: s.AvgLatency, Throughput: s.Throughput})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return rankValue(rankBy, entries[i]) > rankValue(rankBy, entries[j])
+		})
+		if topN > 0 && topN < len(entries) {
+			entries = entries[:topN]
+		}
+
+		fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
+		fmt.Printf("│                    HOTSPOTS (Carbon-Aware, ranked by %-10s)                       │\n", rankBy)
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		fmt.Printf("│ %-28s │ %8s │ %10s │ %11s │ %10s │\n", "Sub-cluster / Region", "Jobs", "Avg Carbon", "Utilization", "Latency")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		for _, e := range entries {
+			fmt.Printf("│ %-28s │ %8d │ %10.2f │ %10.2f%% │ %10s │\n", e.Label, e.Jobs, e.AvgCarbon, e.Utilization, formatDuration(e.Latency))
+		}
+		fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
+		fmt.Println()
+	}
+
 	// Performance metrics
 	fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
 	fmt.Println("│                          PERFORMANCE METRICS                                         │")
@@ -877,7 +1755,130 @@ func generateReport(results []SimulationResults, cache *CarbonCache) {
 	fmt.Printf("│ Average Node Utilization:    %.2f%%                                                    │\n", carbonAware.AverageUtilization)
 	fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
 	fmt.Println()
-	
+
+	// Dominant Resource Fairness per-tenant breakdown, compared against
+	// Round-Robin's tenant stats as an "unfair" equal-weight baseline
+	var drf, unfair SimulationResults
+	for _, r := range results {
+		if r.SchedulerType == "Dominant-Resource-Fairness" {
+			drf = r
+		}
+		if r.SchedulerType == "Round-Robin" {
+			unfair = r
+		}
+	}
+	if len(drf.TenantStats) > 0 {
+		tenants := make([]string, 0, len(drf.TenantStats))
+		for tenant := range drf.TenantStats {
+			tenants = append(tenants, tenant)
+		}
+		sort.Strings(tenants)
+
+		fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
+		fmt.Println("│                 DOMINANT RESOURCE FAIRNESS — PER-TENANT OUTCOMES                     │")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		fmt.Printf("│ %-12s │ %12s │ %10s │ %12s │ %10s │ %12s │\n", "Tenant", "DRF Share", "DRF Jobs", "DRF CO2", "RR Share", "RR Jobs")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		for _, tenant := range tenants {
+			drfStat := drf.TenantStats[tenant]
+			rrStat := unfair.TenantStats[tenant]
+			fmt.Printf("│ %-12s │ %12.4f │ %10d │ %12.2f │ %10.4f │ %12d │\n",
+				tenant, drfStat.DominantShare, drfStat.JobsServed, drfStat.CO2Attributed, rrStat.DominantShare, rrStat.JobsServed)
+		}
+		fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
+		fmt.Println("  (Lower, more even dominant shares under DRF indicate fairer allocation than Round-Robin,")
+		fmt.Println("   which assigns jobs without regard to each tenant's accumulated CPU/memory usage.)")
+		fmt.Println()
+	}
+
+	// Preemption cost for the Carbon-Aware-Preemptive scheduler
+	var preemptive SimulationResults
+	for _, r := range results {
+		if r.SchedulerType == "Carbon-Aware-Preemptive" {
+			preemptive = r
+		}
+	}
+	if preemptive.SchedulerType != "" {
+		fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
+		fmt.Println("│                     CARBON-AWARE-PREEMPTIVE — EVICTION COST                          │")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		fmt.Printf("│ Preempted Jobs:              %-6d                                                    │\n", preemptive.PreemptedJobs)
+		fmt.Printf("│ Wasted CPU-Hours:            %.2f                                                     │\n", preemptive.WastedCPUHours)
+		fmt.Printf("│ Carbon Wasted on Preemption: %.2f g CO2/kWh                                            │\n", preemptive.CarbonWastedOnPreemption)
+		fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
+		fmt.Println()
+	}
+
+	// Deferral savings for the Carbon-Aware-Deferred scheduler
+	var deferred SimulationResults
+	for _, r := range results {
+		if r.SchedulerType == "Carbon-Aware-Deferred" {
+			deferred = r
+		}
+	}
+	if deferred.SchedulerType != "" {
+		fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
+		fmt.Println("│                   CARBON-AWARE-DEFERRED — TIME-SHIFT SAVINGS                         │")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		fmt.Printf("│ Deferred Jobs:               %-6d                                                    │\n", deferred.DeferredJobs)
+		fmt.Printf("│ Average Deferral:            %.1f minutes                                             │\n", deferred.AverageDeferralMinutes)
+		fmt.Printf("│ Carbon Saved by Deferral:    %.2f g CO2/kWh                                            │\n", deferred.CarbonSavedByDeferral)
+		fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
+		fmt.Println()
+	}
+
+	// Per-pool breakdown for the carbon-aware scheduler, showing how
+	// utilization, carbon, and queue depth differ across the "on-demand",
+	// "spot", and "gpu" pools, plus how often a saturated pool borrowed
+	// capacity from a greener one.
+	if len(carbonAware.PoolStats) > 0 {
+		pools := make([]string, 0, len(carbonAware.PoolStats))
+		for pool := range carbonAware.PoolStats {
+			pools = append(pools, pool)
+		}
+		sort.Strings(pools)
+
+		fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
+		fmt.Println("│                          MULTI-POOL BREAKDOWN (Carbon-Aware)                         │")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		fmt.Printf("│ %-12s │ %12s │ %14s │ %10s │ %10s │\n", "Pool", "Utilization", "Avg Carbon", "Queue", "Jobs")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		for _, pool := range pools {
+			stats := carbonAware.PoolStats[pool]
+			fmt.Printf("│ %-12s │ %11.2f%% │ %14.2f │ %10d │ %10d │\n", pool, stats.Utilization, stats.AverageCarbon, stats.QueueDepth, stats.JobsServed)
+		}
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		fmt.Printf("│ Cross-Pool Borrows: %-6d                                                              │\n", carbonAware.CrossPoolBorrowCount)
+		fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
+		fmt.Println()
+	}
+
+	// Time-weighted utilization stats for the carbon-aware scheduler, sourced
+	// from the UtilizationGatherer's sampled series rather than an end-of-run
+	// snapshot, so P95 reflects sustained load rather than a single instant.
+	if len(carbonAware.NodeUtilizationP95) > 0 {
+		nodeNames := make([]string, 0, len(carbonAware.NodeUtilizationP95))
+		for name := range carbonAware.NodeUtilizationP95 {
+			nodeNames = append(nodeNames, name)
+		}
+		sort.Strings(nodeNames)
+
+		fmt.Println("┌──────────────────────────────────────────────────────────────────────────────────────┐")
+		fmt.Println("│                   TIME-SAMPLED NODE UTILIZATION (Carbon-Aware)                       │")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		fmt.Printf("│ %-20s │ %10s │ %10s │ %10s │\n", "Node", "Mean CPU%", "P95 CPU%", "P95 Mem%")
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		for _, name := range nodeNames {
+			fmt.Printf("│ %-20s │ %9.2f%% │ %9.2f%% │ %9.2f%% │\n",
+				name, carbonAware.NodeUtilization[name], carbonAware.NodeUtilizationP95[name], carbonAware.NodeMemP95[name])
+		}
+		fmt.Println("├──────────────────────────────────────────────────────────────────────────────────────┤")
+		fmt.Printf("│ Cluster Mean CPU%%: %6.2f%%   P95: %6.2f%%   P99: %6.2f%%   Peak: %6.2f%%                  │\n",
+			carbonAware.ClusterUtilSeries.Mean, carbonAware.ClusterUtilSeries.P95, carbonAware.ClusterUtilSeries.P99, carbonAware.ClusterUtilSeries.Peak)
+		fmt.Println("└──────────────────────────────────────────────────────────────────────────────────────┘")
+		fmt.Println()
+	}
+
 	// Summary
 	fmt.Println("╔══════════════════════════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║                                  SUMMARY                                              ║")
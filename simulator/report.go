@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// ReportFormat selects which ReportWriter implementation writeReport uses.
+type ReportFormat string
+
+const (
+	ReportFormatText ReportFormat = "text"
+	ReportFormatJSON ReportFormat = "json"
+	ReportFormatCSV  ReportFormat = "csv"
+)
+
+// ReportWriter renders a completed set of scheduler runs in one output
+// format. The text writer renders the existing boxed ASCII tables to stdout
+// unchanged; the json/csv writers serialize structured data for downstream
+// analysis and CI regression checks.
+type ReportWriter interface {
+	WriteReport(results []SimulationResults, cache *CarbonCache) error
+}
+
+// newReportWriter resolves format (any value other than "json"/"csv" falls
+// back to the existing ASCII text report) to its ReportWriter. outPath is
+// where json/csv output is written; empty means stdout. rankBy/topN only
+// affect the text report's HOTSPOTS table.
+func newReportWriter(format ReportFormat, outPath, rankBy string, topN int) ReportWriter {
+	switch format {
+	case ReportFormatJSON:
+		return jsonReportWriter{outPath: outPath}
+	case ReportFormatCSV:
+		return csvReportWriter{outPath: outPath}
+	default:
+		return textReportWriter{rankBy: rankBy, topN: topN}
+	}
+}
+
+// writeReportOutput writes data to outPath, or to stdout if outPath is empty.
+func writeReportOutput(outPath string, data []byte) error {
+	if outPath == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(outPath, data, 0644)
+}
+
+// textReportWriter reproduces the original boxed ASCII report to stdout, plus
+// a HOTSPOTS table ranking sub-clusters and regions by rankBy, limited to topN.
+type textReportWriter struct {
+	rankBy string
+	topN   int
+}
+
+func (w textReportWriter) WriteReport(results []SimulationResults, cache *CarbonCache) error {
+	generateReport(results, cache, w.rankBy, w.topN)
+	return nil
+}
+
+// jsonSchedulerResult embeds the full SimulationResults for one scheduler,
+// plus deltas computed relative to the worst-case scheduler.
+type jsonSchedulerResult struct {
+	SimulationResults
+	CarbonReductionVsWorstPercent  float64 `json:"carbonReductionVsWorstPercent"`
+	LatencyReductionVsWorstPercent float64 `json:"latencyReductionVsWorstPercent"`
+}
+
+type jsonReport struct {
+	Schedulers []jsonSchedulerResult `json:"schedulers"`
+}
+
+// jsonReportWriter serializes the full SimulationResults slice, including
+// per-region breakdowns, per-node utilization summaries, and deltas vs. the
+// worst-case scheduler, as indented JSON.
+type jsonReportWriter struct {
+	outPath string
+}
+
+func (w jsonReportWriter) WriteReport(results []SimulationResults, cache *CarbonCache) error {
+	worstCase := findSchedulerResult(results, "Highest-Carbon (Worst Case)")
+
+	report := jsonReport{Schedulers: make([]jsonSchedulerResult, 0, len(results))}
+	for _, r := range results {
+		entry := jsonSchedulerResult{SimulationResults: r}
+		if worstCase.AverageCarbon > 0 {
+			entry.CarbonReductionVsWorstPercent = ((worstCase.AverageCarbon - r.AverageCarbon) / worstCase.AverageCarbon) * 100.0
+		}
+		if worstCase.AverageLatency > 0 {
+			entry.LatencyReductionVsWorstPercent = ((float64(worstCase.AverageLatency) - float64(r.AverageLatency)) / float64(worstCase.AverageLatency)) * 100.0
+		}
+		report.Schedulers = append(report.Schedulers, entry)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON report: %w", err)
+	}
+	return writeReportOutput(w.outPath, data)
+}
+
+// csvReportWriter emits one row per (scheduler, region), with jobs, avg/p95
+// carbon, avg/p95 latency and turnaround, throughput, and carbon reduction %.
+type csvReportWriter struct {
+	outPath string
+}
+
+func (w csvReportWriter) WriteReport(results []SimulationResults, cache *CarbonCache) error {
+	worstCase := findSchedulerResult(results, "Highest-Carbon (Worst Case)")
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{
+		"scheduler", "region", "jobs",
+		"avg_carbon_gco2_per_kwh", "p95_carbon_gco2_per_kwh",
+		"avg_latency_seconds", "p95_latency_seconds",
+		"avg_turnaround_seconds", "p95_turnaround_seconds",
+		"throughput_jobs_per_hour", "carbon_reduction_percent",
+	})
+
+	for _, r := range results {
+		reduction := 0.0
+		if worstCase.AverageCarbon > 0 {
+			reduction = ((worstCase.AverageCarbon - r.AverageCarbon) / worstCase.AverageCarbon) * 100.0
+		}
+
+		regions := make([]string, 0, len(r.JobsByRegion))
+		for region := range r.JobsByRegion {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+
+		for _, region := range regions {
+			jobs := r.JobsByRegion[region]
+			avgCarbon := 0.0
+			if jobs > 0 {
+				avgCarbon = r.CarbonByRegion[region] / float64(jobs)
+			}
+			writer.Write([]string{
+				r.SchedulerType,
+				region,
+				strconv.Itoa(jobs),
+				strconv.FormatFloat(avgCarbon, 'f', 2, 64),
+				strconv.FormatFloat(r.CarbonByRegionP95[region], 'f', 2, 64),
+				strconv.FormatFloat(r.AverageLatency.Seconds(), 'f', 3, 64),
+				strconv.FormatFloat(r.P95Latency.Seconds(), 'f', 3, 64),
+				strconv.FormatFloat(r.AverageTurnaround.Seconds(), 'f', 3, 64),
+				strconv.FormatFloat(r.P95Turnaround.Seconds(), 'f', 3, 64),
+				strconv.FormatFloat(r.Throughput, 'f', 2, 64),
+				strconv.FormatFloat(reduction, 'f', 2, 64),
+			})
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV report: %w", err)
+	}
+	return writeReportOutput(w.outPath, buf.Bytes())
+}
+
+// findSchedulerResult returns the SimulationResults entry matching
+// schedulerType, or the zero value if not present among results.
+func findSchedulerResult(results []SimulationResults, schedulerType string) SimulationResults {
+	for _, r := range results {
+		if r.SchedulerType == schedulerType {
+			return r
+		}
+	}
+	return SimulationResults{}
+}
+
+// reportSummary is the pinned set of carbon-aware reduction metrics written
+// to summary.json, intended for CI to assert against (e.g. "reduction must
+// stay above N%") without parsing the full report.
+type reportSummary struct {
+	SchedulerType          string  `json:"schedulerType"`
+	CompletedJobs          int     `json:"completedJobs"`
+	FailedJobs             int     `json:"failedJobs"`
+	AverageCarbon          float64 `json:"averageCarbonGCO2PerKWh"`
+	P95Carbon              float64 `json:"p95CarbonGCO2PerKWh"`
+	CarbonReductionPercent float64 `json:"carbonReductionPercent"`
+	ThroughputJobsPerHour  float64 `json:"throughputJobsPerHour"`
+}
+
+// writeSummaryJSON writes the carbon-aware scheduler's pinned reduction
+// metrics to path. A no-op if path is empty.
+func writeSummaryJSON(results []SimulationResults, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	carbonAware := findSchedulerResult(results, "Carbon-Aware")
+	worstCase := findSchedulerResult(results, "Highest-Carbon (Worst Case)")
+
+	reduction := 0.0
+	if worstCase.AverageCarbon > 0 {
+		reduction = ((worstCase.AverageCarbon - carbonAware.AverageCarbon) / worstCase.AverageCarbon) * 100.0
+	}
+
+	summary := reportSummary{
+		SchedulerType:          carbonAware.SchedulerType,
+		CompletedJobs:          carbonAware.CompletedJobs,
+		FailedJobs:             carbonAware.FailedJobs,
+		AverageCarbon:          carbonAware.AverageCarbon,
+		P95Carbon:              carbonAware.P95Carbon,
+		CarbonReductionPercent: reduction,
+		ThroughputJobsPerHour:  carbonAware.Throughput,
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary.json: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
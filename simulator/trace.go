@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// EventType enumerates the job state transitions recorded by the optional
+// --eventsOutputFilePath trace.
+type EventType string
+
+const (
+	EventArrived   EventType = "arrived"
+	EventQueued    EventType = "queued"
+	EventScheduled EventType = "scheduled"
+	EventStarted   EventType = "started"
+	EventCompleted EventType = "completed"
+	EventPreempted EventType = "preempted"
+)
+
+// JobEvent is one line of the --eventsOutputFilePath JSON trace, emitted on
+// every job state transition so downstream analysis notebooks can reconstruct
+// a job's full timeline without re-running the simulation.
+type JobEvent struct {
+	Timestamp       time.Time `json:"timestamp"`
+	SchedulerType   string    `json:"schedulerType"`
+	JobID           string    `json:"jobId"`
+	Event           EventType `json:"event"`
+	Node            string    `json:"node,omitempty"`
+	Region          string    `json:"region,omitempty"`
+	CarbonIntensity float64   `json:"carbonIntensity,omitempty"`
+}
+
+// CycleStats is one line of the --cycleStatsOutputFilePath JSON trace,
+// recorded once per scheduling tick.
+type CycleStats struct {
+	Timestamp        time.Time      `json:"timestamp"`
+	SchedulerType    string         `json:"schedulerType"`
+	JobsScheduled    int            `json:"jobsScheduled"`
+	QueueDepths      map[string]int `json:"queueDepths"`
+	CarbonConsumed   float64        `json:"carbonConsumed"`
+	CycleWallTimeSec float64        `json:"cycleWallTimeSec"`
+}
+
+// Tracer writes JobEvent/CycleStats JSON lines to the files named by
+// --eventsOutputFilePath/--cycleStatsOutputFilePath. A nil *Tracer is valid
+// and makes every recording method a no-op, so tracing adds no overhead
+// unless a caller opts in.
+type Tracer struct {
+	mu         sync.Mutex
+	eventsFile *os.File
+	cycleFile  *os.File
+}
+
+// NewTracer opens the requested trace output files. Either path may be empty
+// to skip that trace; if both are empty NewTracer returns a nil *Tracer.
+func NewTracer(eventsPath, cycleStatsPath string) (*Tracer, error) {
+	if eventsPath == "" && cycleStatsPath == "" {
+		return nil, nil
+	}
+
+	t := &Tracer{}
+	if eventsPath != "" {
+		f, err := os.Create(eventsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create events output file: %w", err)
+		}
+		t.eventsFile = f
+	}
+	if cycleStatsPath != "" {
+		f, err := os.Create(cycleStatsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cycle stats output file: %w", err)
+		}
+		t.cycleFile = f
+	}
+	return t, nil
+}
+
+// RecordEvent appends a JobEvent as one JSON line. No-op on a nil Tracer or
+// when --eventsOutputFilePath wasn't set.
+func (t *Tracer) RecordEvent(ev JobEvent) {
+	if t == nil || t.eventsFile == nil {
+		return
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.eventsFile.Write(append(data, '\n'))
+}
+
+// RecordCycle appends a CycleStats as one JSON line. No-op on a nil Tracer or
+// when --cycleStatsOutputFilePath wasn't set.
+func (t *Tracer) RecordCycle(stats CycleStats) {
+	if t == nil || t.cycleFile == nil {
+		return
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cycleFile.Write(append(data, '\n'))
+}
+
+// Close closes whichever trace files were opened. Safe to call on a nil Tracer.
+func (t *Tracer) Close() {
+	if t == nil {
+		return
+	}
+	if t.eventsFile != nil {
+		t.eventsFile.Close()
+	}
+	if t.cycleFile != nil {
+		t.cycleFile.Close()
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// UtilizationSummary summarizes a bounded series of percentage samples (CPU or
+// memory) taken over the course of a run.
+type UtilizationSummary struct {
+	Mean float64
+	P50  float64
+	P90  float64
+	P95  float64
+	P99  float64
+	Peak float64
+}
+
+// utilizationSample is one (CPU%, memory%) observation of a node at a point
+// in simulated time.
+type utilizationSample struct {
+	cpuPercent float64
+	memPercent float64
+}
+
+// sampleTick is one Sample() call's worth of per-node observations, handed to
+// the gatherer goroutine over sampleCh.
+type sampleTick struct {
+	samples map[string]utilizationSample
+}
+
+// UtilizationGatherer time-samples each node's CPU/memory utilization at a
+// configurable interval of simulated time, analogous to Kubernetes'
+// resource_usage_gatherer, and summarizes the resulting series into
+// mean/percentile/peak stats per node plus a cluster-wide aggregate. Unlike a
+// real-time gatherer it is not driven by a time.Ticker: runSimulation's loop
+// advances through simulated time synchronously, so Sample is called once per
+// tick with the simulated currentTime and throttled internally against
+// config.UtilizationSampleInterval.
+type UtilizationGatherer struct {
+	interval   time.Duration
+	bufferSize int
+
+	lastSample time.Time
+	sampled    bool
+
+	sampleCh chan sampleTick
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+
+	cpuSeries     map[string][]float64
+	memSeries     map[string][]float64
+	clusterSeries []float64
+}
+
+// NewUtilizationGatherer starts the gatherer's background goroutine. interval
+// and bufferSize fall back to sane defaults if non-positive, so a zero-value
+// SimulationConfig still produces a usable gatherer.
+func NewUtilizationGatherer(interval time.Duration, bufferSize int) *UtilizationGatherer {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if bufferSize <= 0 {
+		bufferSize = 1000
+	}
+
+	g := &UtilizationGatherer{
+		interval:   interval,
+		bufferSize: bufferSize,
+		sampleCh:   make(chan sampleTick),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+		cpuSeries:  make(map[string][]float64),
+		memSeries:  make(map[string][]float64),
+	}
+	go g.run()
+	return g
+}
+
+// run drains sampleCh, appending each node's sample into its bounded ring
+// buffer, until Stop closes stopCh.
+func (g *UtilizationGatherer) run() {
+	defer close(g.doneCh)
+	for {
+		select {
+		case tick := <-g.sampleCh:
+			var clusterCPU float64
+			for name, s := range tick.samples {
+				g.cpuSeries[name] = appendBounded(g.cpuSeries[name], s.cpuPercent, g.bufferSize)
+				g.memSeries[name] = appendBounded(g.memSeries[name], s.memPercent, g.bufferSize)
+				clusterCPU += s.cpuPercent
+			}
+			if len(tick.samples) > 0 {
+				g.clusterSeries = appendBounded(g.clusterSeries, clusterCPU/float64(len(tick.samples)), g.bufferSize)
+			}
+		case <-g.stopCh:
+			return
+		}
+	}
+}
+
+// appendBounded appends value to series, dropping the oldest sample once
+// bufferSize is exceeded so long runs don't grow the series unboundedly.
+func appendBounded(series []float64, value float64, bufferSize int) []float64 {
+	series = append(series, value)
+	if len(series) > bufferSize {
+		series = series[len(series)-bufferSize:]
+	}
+	return series
+}
+
+// Sample records one observation per node at currentTime, throttled to at
+// most once per g.interval of simulated time so calling it every tick costs
+// nothing between samples. Nodes with no running jobs still report 0%
+// utilization, so a job completing between samples is reflected by the very
+// next one.
+func (g *UtilizationGatherer) Sample(currentTime time.Time, nodes []*Node) {
+	if g == nil {
+		return
+	}
+	if g.sampled && currentTime.Sub(g.lastSample) < g.interval {
+		return
+	}
+	g.sampled = true
+	g.lastSample = currentTime
+
+	tick := sampleTick{samples: make(map[string]utilizationSample, len(nodes))}
+	for _, node := range nodes {
+		cpuPct := 0.0
+		memPct := 0.0
+		if node.TotalCPU > 0 {
+			cpuPct = (node.TotalCPU - node.AvailableCPU) / node.TotalCPU * 100.0
+		}
+		if node.TotalMemory > 0 {
+			memPct = (node.TotalMemory - node.AvailableMemory) / node.TotalMemory * 100.0
+		}
+		tick.samples[node.Name] = utilizationSample{cpuPercent: cpuPct, memPercent: memPct}
+	}
+	g.sampleCh <- tick
+}
+
+// Stop shuts down the gatherer's goroutine and waits for it to drain.
+func (g *UtilizationGatherer) Stop() {
+	if g == nil {
+		return
+	}
+	close(g.stopCh)
+	<-g.doneCh
+}
+
+// NodeUtilizationSummaries summarizes the collected series into per-node CPU
+// and memory UtilizationSummary maps plus a cluster-wide CPU summary. Call
+// this after Stop has returned, so the gatherer goroutine has fully drained
+// and its series maps are safe to read without synchronization.
+func (g *UtilizationGatherer) NodeUtilizationSummaries() (cpu, mem map[string]UtilizationSummary, cluster UtilizationSummary) {
+	cpu = make(map[string]UtilizationSummary, len(g.cpuSeries))
+	for name, series := range g.cpuSeries {
+		cpu[name] = summarize(series)
+	}
+	mem = make(map[string]UtilizationSummary, len(g.memSeries))
+	for name, series := range g.memSeries {
+		mem[name] = summarize(series)
+	}
+	cluster = summarize(g.clusterSeries)
+	return cpu, mem, cluster
+}
+
+// summarize computes mean/P50/P90/P95/P99/peak over a series of percentage
+// samples, returning the zero UtilizationSummary for an empty series.
+func summarize(series []float64) UtilizationSummary {
+	if len(series) == 0 {
+		return UtilizationSummary{}
+	}
+
+	sorted := make([]float64, len(series))
+	copy(sorted, series)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+
+	return UtilizationSummary{
+		Mean: sum / float64(len(sorted)),
+		P50:  percentile(sorted, 0.50),
+		P90:  percentile(sorted, 0.90),
+		P95:  percentile(sorted, 0.95),
+		P99:  percentile(sorted, 0.99),
+		Peak: sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the value at fraction p (0-1) of a pre-sorted series.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}